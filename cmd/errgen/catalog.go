@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/coffeehc/base/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	classMessage = "message"
+	classSystem  = "system"
+)
+
+// catalog 错误码目录
+type catalog struct {
+	Package  string      `yaml:"package"`
+	Domain   string      `yaml:"domain"`
+	DomainID int64       `yaml:"domain_id"`
+	Module   string      `yaml:"module"`
+	ModuleID int64       `yaml:"module_id"`
+	Prefix   string      `yaml:"prefix"`
+	Codes    []*codeSpec `yaml:"codes"`
+
+	// Scope 模块的错误码范围,由 resolve 计算
+	Scope int64 `yaml:"-"`
+}
+
+type codeSpec struct {
+	Name        string            `yaml:"name"`
+	Class       string            `yaml:"class"`
+	Reason      int64             `yaml:"reason"`
+	Code        int64             `yaml:"code"`
+	Description string            `yaml:"description"`
+	Message     string            `yaml:"message"`
+	Messages    map[string]string `yaml:"messages"`
+
+	// RegistryName 注册到 errors 的名称,由 resolve 计算
+	RegistryName string `yaml:"-"`
+}
+
+func loadCatalog(path string) (*catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &catalog{}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// resolve 校验目录并计算错误码和注册名称
+func (c *catalog) resolve() error {
+	if !token.IsIdentifier(c.Package) {
+		return fmt.Errorf("包名 %q 不是合法的标识符", c.Package)
+	}
+	if c.Domain != "" && c.Module == "" {
+		return fmt.Errorf("设置 domain 时必须设置 module")
+	}
+	if len(c.Codes) == 0 {
+		return fmt.Errorf("没有定义错误码")
+	}
+	module, err := c.module()
+	if err != nil {
+		return err
+	}
+	if c.Prefix == "" && module != nil {
+		c.Prefix = snakeCase(c.Domain) + "_" + snakeCase(c.Module)
+	}
+	names := make(map[string]bool)
+	codes := make(map[int64]string)
+	for _, spec := range c.Codes {
+		if !token.IsIdentifier(spec.Name) || !token.IsExported(spec.Name) {
+			return fmt.Errorf("错误码名称 %q 必须是导出的标识符", spec.Name)
+		}
+		if names[spec.Name] {
+			return fmt.Errorf("错误码名称 %s 重复", spec.Name)
+		}
+		names[spec.Name] = true
+		if spec.Class == "" {
+			spec.Class = classMessage
+		}
+		if spec.Class != classMessage && spec.Class != classSystem {
+			return fmt.Errorf("%s: class 只能是 message 或 system", spec.Name)
+		}
+		if err := spec.resolveCode(module); err != nil {
+			return fmt.Errorf("%s: %w", spec.Name, err)
+		}
+		if exist, ok := codes[spec.Code]; ok {
+			return fmt.Errorf("%s 与 %s 的错误码 0x%x 重复", spec.Name, exist, spec.Code)
+		}
+		codes[spec.Code] = spec.Name
+		spec.RegistryName = snakeCase(spec.Name)
+		if c.Prefix != "" {
+			spec.RegistryName = c.Prefix + "_" + spec.RegistryName
+		}
+		if spec.Message == "" {
+			spec.Message = spec.Description
+		}
+	}
+	return nil
+}
+
+// module 计算领域和模块编号,与运行时 errors.NewDomain/Module 的分配结果一致
+func (c *catalog) module() (module *errors.Module, err error) {
+	if c.Domain == "" {
+		return nil, nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	var domain *errors.Domain
+	if c.DomainID != 0 {
+		domain = errors.NewDomainWithID(c.Domain, c.DomainID)
+	} else {
+		domain = errors.NewDomain(c.Domain)
+	}
+	c.DomainID = errors.SplitCode(domain.Scope()).Domain
+	if c.ModuleID != 0 {
+		module = domain.ModuleWithID(c.Module, c.ModuleID)
+	} else {
+		module = domain.Module(c.Module)
+	}
+	c.ModuleID = errors.SplitCode(module.Scope()).Module
+	c.Scope = module.Scope()
+	return module, nil
+}
+
+func (spec *codeSpec) resolveCode(module *errors.Module) (err error) {
+	if spec.Code != 0 {
+		if !errors.IsBaseErrorCode(spec.Code) {
+			return fmt.Errorf("错误码 0x%x 不是 base 错误码", spec.Code)
+		}
+		if spec.classCode() != errors.SplitCode(spec.Code).Class {
+			return fmt.Errorf("错误码 0x%x 与 class %s 不一致", spec.Code, spec.Class)
+		}
+		return nil
+	}
+	if module == nil {
+		return fmt.Errorf("没有设置 domain 时必须指定 code")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	if spec.Class == classSystem {
+		spec.Code = module.SystemCode(spec.Reason)
+	} else {
+		spec.Code = module.Code(spec.Reason)
+	}
+	return nil
+}
+
+func (spec *codeSpec) classCode() int64 {
+	if spec.Class == classSystem {
+		return errors.SplitCode(errors.ErrorSystem).Class
+	}
+	return errors.SplitCode(errors.ErrorMessage).Class
+}
+
+// languages 返回所有国际化消息的语言,按名称排序
+func (c *catalog) languages() []string {
+	set := make(map[string]bool)
+	for _, spec := range c.Codes {
+		for lang := range spec.Messages {
+			set[lang] = true
+		}
+	}
+	langs := make([]string, 0, len(set))
+	for lang := range set {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// snakeCase OrderClosed -> ORDER_CLOSED, order-service -> ORDER_SERVICE
+func snakeCase(s string) string {
+	var builder strings.Builder
+	runes := []rune(s)
+	separated := true
+	for i, r := range runes {
+		if r == '-' || r == '.' || r == ' ' || r == '_' {
+			if !separated {
+				builder.WriteByte('_')
+			}
+			separated = true
+			continue
+		}
+		if unicode.IsUpper(r) && !separated &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			builder.WriteByte('_')
+		}
+		builder.WriteRune(unicode.ToUpper(r))
+		separated = false
+	}
+	return strings.TrimSuffix(builder.String(), "_")
+}
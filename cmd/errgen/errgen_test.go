@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/coffeehc/base/errors"
+)
+
+const testCatalog = `
+domain: errgen-test
+module: payment
+codes:
+  - name: OrderClosed
+    reason: 1
+    description: 订单已关闭
+    messages:
+      en: "order {{.orderId}} is closed"
+  - name: GatewayDown
+    class: system
+    reason: 2
+    description: 支付网关不可用
+`
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "ordererr")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	in := filepath.Join(dir, "errors.yaml")
+	if err := os.WriteFile(in, []byte(testCatalog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, doc := filepath.Join(dir, "errors_gen.go"), filepath.Join(dir, "ERRORS.md")
+	if err := run(in, out, doc); err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, out)
+	source, _ := os.ReadFile(out)
+	module := errors.NewDomain("errgen-test").Module("payment")
+	for _, want := range []string{
+		"package ordererr",
+		"CodeOrderClosed int64 = 0x" + strconv.FormatInt(module.Code(1), 16),
+		"CodeGatewayDown int64 = 0x" + strconv.FormatInt(module.SystemCode(2), 16),
+		`Name: "ERRGEN_TEST_PAYMENT_ORDER_CLOSED"`,
+		`"ERRGEN_TEST_PAYMENT_ORDER_CLOSED": "order {{.orderId}} is closed"`,
+		"func NewOrderClosed(message string, opts ...errors.Option) errors.Error",
+		"func WrapGatewayDown(err error, opts ...errors.Option) errors.Error",
+		"func IsGatewayDown(err error) bool",
+		`ErrOrderClosed = errors.NewSentinel(CodeOrderClosed, "订单已关闭")`,
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Fatalf("generated code should contain %q:\n%s", want, source)
+		}
+	}
+	markdown, _ := os.ReadFile(doc)
+	if !strings.Contains(string(markdown), "| `ERRGEN_TEST_PAYMENT_GATEWAY_DOWN` | system | 支付网关不可用 |") {
+		t.Fatalf("unexpected doc:\n%s", markdown)
+	}
+}
+
+// typeCheck 在模块内编译生成的代码并运行 vet,确保引用的 errors API 和标识符都存在
+func typeCheck(t *testing.T, file string) {
+	t.Helper()
+	dir, err := os.MkdirTemp(".", "_errgen-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	source, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, filepath.Base(file)), source, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if output, err := exec.Command("go", "vet", "./"+dir).CombinedOutput(); err != nil {
+		t.Fatalf("generated code does not compile: %v\n%s", err, output)
+	}
+}
+
+func TestExplicitIDs(t *testing.T) {
+	// 另一个同名领域下按名称哈希得到的模块编号,在指定编号的领域中被其他模块占用
+	hashed := errors.SplitCode(errors.NewDomainWithID("errgen-explicit", 424242).Module("payment").Scope()).Module
+	errors.NewDomainWithID("errgen-explicit", 424243).ModuleWithID("occupied", hashed)
+	c := &catalog{Package: "p", Domain: "errgen-explicit", DomainID: 424243, Module: "payment", ModuleID: hashed%4095 + 1,
+		Codes: []*codeSpec{{Name: "A", Reason: 1}}}
+	if err := c.resolve(); err != nil {
+		t.Fatalf("explicit ids should not allocate hashed ids: %v", err)
+	}
+	if split := errors.SplitCode(c.Scope); split.Domain != 424243 || split.Module != hashed%4095+1 {
+		t.Fatalf("unexpected scope 0x%x", c.Scope)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	cases := map[string]*catalog{
+		"missing code":   {Package: "p", Codes: []*codeSpec{{Name: "A"}}},
+		"bad class":      {Package: "p", Codes: []*codeSpec{{Name: "A", Class: "x", Code: errors.ErrorMessage | 0x10}}},
+		"class mismatch": {Package: "p", Codes: []*codeSpec{{Name: "A", Class: classSystem, Code: errors.ErrorMessage | 0x10}}},
+		"duplicate code": {Package: "p", Codes: []*codeSpec{{Name: "A", Code: errors.ErrorMessage | 0x10}, {Name: "B", Code: errors.ErrorMessage | 0x10}}},
+		"unexported":     {Package: "p", Codes: []*codeSpec{{Name: "a", Code: errors.ErrorMessage | 0x10}}},
+		"bad reason":     {Package: "p", Domain: "errgen-test", Module: "payment", Codes: []*codeSpec{{Name: "A", Reason: 0}}},
+	}
+	for name, c := range cases {
+		if err := c.resolve(); err == nil {
+			t.Fatalf("%s: resolve should fail", name)
+		}
+	}
+	if snakeCase("HTTPGatewayDown") != "HTTP_GATEWAY_DOWN" || snakeCase("order-service.v2") != "ORDER_SERVICE_V2" {
+		t.Fatal("unexpected snake case")
+	}
+}
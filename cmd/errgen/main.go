@@ -0,0 +1,76 @@
+// errgen 根据错误码目录(yaml)生成错误码常量、构建函数、判断函数、注册代码和文档
+//
+// 用法:
+//
+//	//go:generate go run github.com/coffeehc/base/cmd/errgen -in errors.yaml -out errors_gen.go -doc ERRORS.md
+//
+// 目录格式:
+//
+//	package: ordererr   # 生成代码的包名,默认为输出目录名
+//	domain: order       # 领域名称,设置后使用结构化错误码
+//	domain_id: 0        # 可选,指定领域编号,0 表示按名称哈希
+//	module: payment     # 模块名称,设置 domain 时必须设置
+//	module_id: 0        # 可选,指定模块编号
+//	prefix: ORDER       # 注册名称的前缀,默认为 领域_模块
+//	codes:
+//	  - name: OrderClosed          # Go 标识符,生成 CodeOrderClosed、ErrOrderClosed、NewOrderClosed、WrapOrderClosed、IsOrderClosed
+//	    class: message             # message(默认) 或 system
+//	    reason: 1                  # 结构化错误码的原因码,范围 1-65535
+//	    code: 0x12000010           # 不使用结构化错误码时直接指定错误码
+//	    description: 订单已关闭
+//	    message: 订单已关闭          # 默认消息
+//	    messages:                  # 国际化消息,见 errors.RegisterMessages
+//	      en: "order {{.orderId}} is closed"
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var (
+		in  = flag.String("in", "errors.yaml", "错误码目录文件")
+		out = flag.String("out", "", "生成的 Go 文件,默认为 <目录文件名>_gen.go")
+		doc = flag.String("doc", "", "生成的 Markdown 文档,为空时不生成")
+	)
+	flag.Parse()
+	if *out == "" {
+		name := filepath.Base(*in)
+		*out = filepath.Join(filepath.Dir(*in), name[:len(name)-len(filepath.Ext(name))]+"_gen.go")
+	}
+	if err := run(*in, *out, *doc); err != nil {
+		fmt.Fprintf(os.Stderr, "errgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, doc string) error {
+	catalog, err := loadCatalog(in)
+	if err != nil {
+		return err
+	}
+	if catalog.Package == "" {
+		abs, err := filepath.Abs(out)
+		if err != nil {
+			return err
+		}
+		catalog.Package = filepath.Base(filepath.Dir(abs))
+	}
+	if err := catalog.resolve(); err != nil {
+		return fmt.Errorf("%s: %w", in, err)
+	}
+	source, err := renderGo(catalog, filepath.Base(in))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, source, 0644); err != nil {
+		return err
+	}
+	if doc == "" {
+		return nil
+	}
+	return os.WriteFile(doc, renderMarkdown(catalog, filepath.Base(in)), 0644)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var goTemplate = template.Must(template.New("go").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+	"hex":   func(code int64) string { return fmt.Sprintf("0x%x", code) },
+}).Parse(`// Code generated by errgen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/coffeehc/base/errors"
+{{if .Domain}}
+// ModuleScope 模块 {{.Domain}}.{{.Module}} 的错误码范围,可以用 errors.MatchError 匹配模块下的所有错误
+const ModuleScope int64 = {{hex .Scope}}
+{{end}}
+const (
+{{- range .Codes}}
+	// Code{{.Name}} {{.Description}}
+	Code{{.Name}} int64 = {{hex .Code}}
+{{- end}}
+)
+
+// 哨兵错误,用于 errors.Is 匹配
+var (
+{{- range .Codes}}
+	Err{{.Name}} = errors.NewSentinel(Code{{.Name}}, {{quote .Message}})
+{{- end}}
+)
+
+func init() {
+{{- if .Domain}}
+	// 占用领域和模块的编号,与其他领域或模块冲突时 panic
+	errors.NewDomainWithID({{quote .Domain}}, {{.DomainID}}).ModuleWithID({{quote .Module}}, {{.ModuleID}})
+{{- end}}
+	errors.RegisterCodes(
+{{- range .Codes}}
+		errors.CodeInfo{Code: Code{{.Name}}, Name: {{quote .RegistryName}}, Description: {{quote .Description}}, Message: {{quote .Message}}},
+{{- end}}
+	)
+{{- range $lang := .Languages}}
+	if err := errors.RegisterMessages({{quote $lang}}, map[string]string{
+{{- range $code := $.Codes}}{{with index $code.Messages $lang}}
+		{{quote $code.RegistryName}}: {{quote .}},
+{{- end}}{{end}}
+	}); err != nil {
+		panic(err)
+	}
+{{- end}}
+}
+{{range .Codes}}
+// New{{.Name}} 构建 Code{{.Name}} 错误, message 为空时使用默认消息
+func New{{.Name}}(message string, opts ...errors.Option) errors.Error {
+	if message == "" {
+		message = {{quote .Message}}
+	}
+	return errors.BuildError(Code{{.Name}}, message, append([]errors.Option{errors.WithCallerSkip(1)}, opts...)...)
+}
+
+// Wrap{{.Name}} 将 err 包装为 Code{{.Name}} 错误, err 为 nil 时返回 nil
+func Wrap{{.Name}}(err error, opts ...errors.Option) errors.Error {
+	if err == nil {
+		return nil
+	}
+	return errors.WrappedError(Code{{.Name}}, err, append([]errors.Option{errors.WithCallerSkip(1), errors.WithPublicMessage({{quote .Message}})}, opts...)...)
+}
+
+// Is{{.Name}} 判断 err 是否为 Code{{.Name}} 错误
+func Is{{.Name}}(err error) bool {
+	return errors.MatchError(err, Code{{.Name}})
+}
+{{end}}`))
+
+func renderGo(c *catalog, source string) ([]byte, error) {
+	var buf bytes.Buffer
+	data := struct {
+		*catalog
+		Source    string
+		Languages []string
+	}{catalog: c, Source: source, Languages: c.languages()}
+	if err := goTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	source2, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化生成的代码失败: %w\n%s", err, buf.String())
+	}
+	return source2, nil
+}
+
+func renderMarkdown(c *catalog, source string) []byte {
+	var builder strings.Builder
+	langs := c.languages()
+	fmt.Fprintf(&builder, "<!-- Code generated by errgen from %s. DO NOT EDIT. -->\n\n", source)
+	fmt.Fprintf(&builder, "# %s 错误码\n\n", c.Package)
+	if c.Domain != "" {
+		fmt.Fprintf(&builder, "领域 `%s`(%d)，模块 `%s`(%d)，错误码范围 `0x%x`。\n\n", c.Domain, c.DomainID, c.Module, c.ModuleID, c.Scope)
+	}
+	builder.WriteString("| 错误码 | 名称 | 类别 | 描述 | 默认消息 |")
+	for _, lang := range langs {
+		fmt.Fprintf(&builder, " %s |", lang)
+	}
+	builder.WriteString("\n|--------|------|------|------|----------|")
+	for range langs {
+		builder.WriteString("------|")
+	}
+	builder.WriteByte('\n')
+	for _, spec := range c.Codes {
+		fmt.Fprintf(&builder, "| `0x%x` | `%s` | %s | %s | %s |", spec.Code, spec.RegistryName, spec.Class,
+			escapeMarkdown(spec.Description), escapeMarkdown(spec.Message))
+		for _, lang := range langs {
+			fmt.Fprintf(&builder, " %s |", escapeMarkdown(spec.Messages[lang]))
+		}
+		builder.WriteByte('\n')
+	}
+	return []byte(builder.String())
+}
+
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
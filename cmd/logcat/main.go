@@ -0,0 +1,280 @@
+// logcat 解码、过滤并输出 protobuf 格式的日志文件
+//
+// 用法:
+//
+//	logcat [flags] [file ...]
+//
+// 未指定文件时从标准输入读取,以 .gz 结尾的文件会自动解压,
+// 指定 -backups 时会按时间顺序先读取 lumberjack 轮转出的备份文件.
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coffeehc/base/log"
+	"go.uber.org/zap/zapcore"
+)
+
+type fieldMatches []string
+
+func (m *fieldMatches) String() string {
+	return strings.Join(*m, ",")
+}
+
+func (m *fieldMatches) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("字段过滤条件必须是 key=value 格式: %s", value)
+	}
+	*m = append(*m, value)
+	return nil
+}
+
+type filter struct {
+	minLevel zapcore.Level
+	since    time.Time
+	until    time.Time
+	logger   string
+	fields   map[string]string
+}
+
+func main() {
+	var (
+		level   = flag.String("level", "", "最小日志级别: debug, info, warn, error, dpanic, panic, fatal")
+		since   = flag.String("since", "", "起始时间(包含),RFC3339 或 2006-01-02T15:04:05")
+		until   = flag.String("until", "", "结束时间(不包含),RFC3339 或 2006-01-02T15:04:05")
+		logger  = flag.String("logger", "", "只输出指定名称(或以 name. 开头)的 logger")
+		format  = flag.String("format", log.FormatConsole, "输出格式: console 或 json")
+		backups = flag.Bool("backups", false, "同时读取轮转出的备份文件")
+		fields  fieldMatches
+	)
+	flag.Var(&fields, "field", "按字段过滤,格式 key=value,嵌套字段使用 a.b,可重复指定")
+	flag.Parse()
+
+	f, err := newFilter(*level, *since, *until, *logger, fields)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *format != log.FormatConsole && *format != log.FormatJSON {
+		fmt.Fprintf(os.Stderr, "不支持的输出格式: %s\n", *format)
+		os.Exit(2)
+	}
+	enc := log.NewEncoder(*format)
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	files := flag.Args()
+	if *backups {
+		files = withBackups(files)
+	}
+	if len(files) == 0 {
+		if err := cat(os.Stdin, enc, f, out); err != nil {
+			out.Flush()
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	exitCode := 0
+	for _, name := range files {
+		if err := catFile(name, enc, f, out); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+			exitCode = 1
+		}
+	}
+	out.Flush()
+	os.Exit(exitCode)
+}
+
+func newFilter(level, since, until, logger string, fields fieldMatches) (*filter, error) {
+	f := &filter{minLevel: zapcore.DebugLevel, logger: logger, fields: make(map[string]string)}
+	if level != "" {
+		lvl, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+		f.minLevel = lvl
+	}
+	var err error
+	if f.since, err = parseTime(since); err != nil {
+		return nil, err
+	}
+	if f.until, err = parseTime(until); err != nil {
+		return nil, err
+	}
+	for _, match := range fields {
+		kv := strings.SplitN(match, "=", 2)
+		f.fields[kv[0]] = kv[1]
+	}
+	return f, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", value, log.TimeLocation)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无法解析时间: %s", value)
+	}
+	return t, nil
+}
+
+func (f *filter) match(body *log.LogBody) bool {
+	if zapcore.Level(body.GetLevel()) < f.minLevel {
+		return false
+	}
+	t := time.Unix(0, body.GetTime())
+	if !f.since.IsZero() && t.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && !t.Before(f.until) {
+		return false
+	}
+	if f.logger != "" && body.GetLoggerName() != f.logger && !strings.HasPrefix(body.GetLoggerName(), f.logger+".") {
+		return false
+	}
+	for key, value := range f.fields {
+		field := lookupField(body.GetFields(), strings.Split(key, "."))
+		if field == nil || fieldString(field) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func lookupField(fields []*log.Field, path []string) *log.Field {
+	for _, field := range fields {
+		if field.GetKey() != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return field
+		}
+		if obj := field.GetObjectValue(); obj != nil {
+			return lookupField(obj.GetFields(), path[1:])
+		}
+	}
+	return nil
+}
+
+func fieldString(field *log.Field) string {
+	switch value := field.GetValue().(type) {
+	case *log.Field_IntValue:
+		return strconv.FormatInt(value.IntValue, 10)
+	case *log.Field_UintValue:
+		return strconv.FormatUint(value.UintValue, 10)
+	case *log.Field_DoubleValue:
+		return strconv.FormatFloat(value.DoubleValue, 'g', -1, 64)
+	case *log.Field_BoolValue:
+		return strconv.FormatBool(value.BoolValue)
+	case *log.Field_StringValue:
+		return value.StringValue
+	case *log.Field_BytesValue:
+		return string(value.BytesValue)
+	case *log.Field_JsonValue:
+		return value.JsonValue
+	case *log.Field_DurationValue:
+		return time.Duration(value.DurationValue).String()
+	case *log.Field_TimeValue:
+		return time.Unix(0, value.TimeValue).In(log.TimeLocation).Format("2006-01-02T15:04:05.000")
+	default:
+		return ""
+	}
+}
+
+func catFile(name string, enc zapcore.Encoder, f *filter, out io.Writer) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return cat(r, enc, f, out)
+}
+
+func cat(r io.Reader, enc zapcore.Encoder, f *filter, out io.Writer) error {
+	reader := log.ReadLogBodies(r)
+	for reader.Next() {
+		body := reader.LogBody()
+		if !f.match(body) {
+			continue
+		}
+		buf, err := log.RenderLogBody(enc, body)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(buf.Bytes())
+		buf.Free()
+		if err != nil {
+			return err
+		}
+	}
+	return reader.Err()
+}
+
+// withBackups 在每个日志文件之前按时间顺序插入其轮转备份
+func withBackups(files []string) []string {
+	result := make([]string, 0, len(files))
+	for _, name := range files {
+		dir := filepath.Dir(name)
+		base := filepath.Base(name)
+		ext := filepath.Ext(base)
+		prefix := base[:len(base)-len(ext)] + "-"
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			result = append(result, name)
+			continue
+		}
+		type backup struct {
+			name string
+			t    time.Time
+			seq  int
+		}
+		backups := make([]backup, 0)
+		for _, entry := range entries {
+			ts := strings.TrimPrefix(entry.Name(), prefix)
+			if entry.IsDir() || ts == entry.Name() {
+				continue
+			}
+			ts = strings.TrimSuffix(strings.TrimSuffix(ts, ".gz"), ext)
+			t, seq, err := log.ParseBackupTimestamp(ts)
+			if err != nil {
+				continue
+			}
+			backups = append(backups, backup{name: filepath.Join(dir, entry.Name()), t: t, seq: seq})
+		}
+		sort.Slice(backups, func(i, j int) bool {
+			if backups[i].t.Equal(backups[j].t) {
+				return backups[i].seq < backups[j].seq
+			}
+			return backups[i].t.Before(backups[j].t)
+		})
+		for _, b := range backups {
+			result = append(result, b.name)
+		}
+		result = append(result, name)
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coffeehc/base/log"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewFilter(t *testing.T) {
+	cases := []struct {
+		name    string
+		level   string
+		since   string
+		until   string
+		fields  fieldMatches
+		wantErr bool
+		check   func(f *filter) bool
+	}{
+		{name: "default", check: func(f *filter) bool {
+			return f.minLevel == zapcore.DebugLevel && f.since.IsZero() && f.until.IsZero() && len(f.fields) == 0
+		}},
+		{name: "level", level: "warn", check: func(f *filter) bool { return f.minLevel == zapcore.WarnLevel }},
+		{name: "bad level", level: "loud", wantErr: true},
+		{name: "rfc3339", since: "2024-01-02T03:04:05Z", check: func(f *filter) bool {
+			return f.since.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		}},
+		{name: "local time", until: "2024-01-02T03:04:05", check: func(f *filter) bool {
+			return f.until.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, log.TimeLocation))
+		}},
+		{name: "bad time", since: "yesterday", wantErr: true},
+		{name: "fields", fields: fieldMatches{"a=1", "b.c=x=y"}, check: func(f *filter) bool {
+			return reflect.DeepEqual(f.fields, map[string]string{"a": "1", "b.c": "x=y"})
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := newFilter(c.level, c.since, c.until, "", c.fields)
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !c.check(f) {
+				t.Fatalf("unexpected filter: %+v", f)
+			}
+		})
+	}
+}
+
+func TestFieldMatchesSet(t *testing.T) {
+	var m fieldMatches
+	if err := m.Set("novalue"); err == nil {
+		t.Fatal("expected error for value without '='")
+	}
+	if err := m.Set("a=b"); err != nil || m.String() != "a=b" {
+		t.Fatalf("unexpected result: %v %s", err, m.String())
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	body := &log.LogBody{
+		Level:      int32(zapcore.InfoLevel),
+		Time:       now.UnixNano(),
+		LoggerName: "svc.db",
+		Fields: []*log.Field{
+			{Key: "user", Value: &log.Field_IntValue{IntValue: 42}},
+			{Key: "req", Value: &log.Field_ObjectValue{ObjectValue: &log.FieldObject{Fields: []*log.Field{
+				{Key: "id", Value: &log.Field_StringValue{StringValue: "r1"}},
+			}}}},
+		},
+	}
+	cases := []struct {
+		name string
+		f    filter
+		want bool
+	}{
+		{"all", filter{minLevel: zapcore.DebugLevel}, true},
+		{"level", filter{minLevel: zapcore.WarnLevel}, false},
+		{"since", filter{minLevel: zapcore.DebugLevel, since: now}, true},
+		{"since after", filter{minLevel: zapcore.DebugLevel, since: now.Add(time.Second)}, false},
+		{"until exclusive", filter{minLevel: zapcore.DebugLevel, until: now}, false},
+		{"logger exact", filter{minLevel: zapcore.DebugLevel, logger: "svc.db"}, true},
+		{"logger prefix", filter{minLevel: zapcore.DebugLevel, logger: "svc"}, true},
+		{"logger partial", filter{minLevel: zapcore.DebugLevel, logger: "sv"}, false},
+		{"field", filter{minLevel: zapcore.DebugLevel, fields: map[string]string{"user": "42"}}, true},
+		{"nested field", filter{minLevel: zapcore.DebugLevel, fields: map[string]string{"req.id": "r1"}}, true},
+		{"field mismatch", filter{minLevel: zapcore.DebugLevel, fields: map[string]string{"req.id": "r2"}}, false},
+		{"field missing", filter{minLevel: zapcore.DebugLevel, fields: map[string]string{"none": ""}}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.f.match(body); got != c.want {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestLookupField(t *testing.T) {
+	fields := []*log.Field{
+		{Key: "a", Value: &log.Field_StringValue{StringValue: "1"}},
+		{Key: "b", Value: &log.Field_ObjectValue{ObjectValue: &log.FieldObject{Fields: []*log.Field{
+			{Key: "c", Value: &log.Field_ObjectValue{ObjectValue: &log.FieldObject{Fields: []*log.Field{
+				{Key: "d", Value: &log.Field_BoolValue{BoolValue: true}},
+			}}}},
+		}}}},
+	}
+	cases := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"a", "1", true},
+		{"b.c.d", "true", true},
+		{"b.c.x", "", false},
+		{"a.b", "", false},
+		{"x", "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			field := lookupField(fields, strings.Split(c.path, "."))
+			if (field != nil) != c.ok {
+				t.Fatalf("expected found=%v, got %v", c.ok, field)
+			}
+			if field != nil && fieldString(field) != c.want {
+				t.Fatalf("expected %q, got %q", c.want, fieldString(field))
+			}
+		})
+	}
+}
+
+func TestWithBackups(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"service.log",
+		"service-2024-01-02T03-04-05.000.log.gz",
+		"service-2024-01-01T03-04-05.000.log",
+		"service-2024-01-03T03-04-05.000.log",
+		"service-2024-01-03T03-04-05.000.1.log",
+		"service-bad.log",
+		"other-2024-01-01T03-04-05.000.log",
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := withBackups([]string{filepath.Join(dir, "service.log"), filepath.Join(dir, "missing", "x.log")})
+	want := []string{
+		filepath.Join(dir, "service-2024-01-01T03-04-05.000.log"),
+		filepath.Join(dir, "service-2024-01-02T03-04-05.000.log.gz"),
+		filepath.Join(dir, "service-2024-01-03T03-04-05.000.log"),
+		filepath.Join(dir, "service-2024-01-03T03-04-05.000.1.log"),
+		filepath.Join(dir, "service.log"),
+		filepath.Join(dir, "missing", "x.log"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
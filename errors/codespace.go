@@ -0,0 +1,215 @@
+package errors
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// 错误码的分段布局:
+//
+//	bit 0-23   原因码(结构化错误码只使用 bit 0-15)
+//	bit 24-27  错误类别, ErrorSystem/ErrorMessage
+//	bit 28     _baseError 标记
+//	bit 32-43  模块
+//	bit 44-62  领域
+//
+// 领域和模块为 0 的错误码为全局错误码,即 errorcode.go 中定义的错误码
+const (
+	reasonMask   int64 = 0xFFFFFF
+	maxReason    int64 = 0xFFFF
+	classMask    int64 = 0xF000000
+	moduleShift        = 32
+	moduleBits         = 12
+	moduleMask   int64 = (1<<moduleBits - 1) << moduleShift
+	domainShift        = 44
+	domainBits         = 19
+	domainMask   int64 = (1<<domainBits - 1) << domainShift
+	maxModuleID  int64 = 1<<moduleBits - 1
+	maxDomainID  int64 = 1<<domainBits - 1
+	baseCodeMask int64 = _baseError
+)
+
+// CodeSegments 错误码拆分后的各个分段
+type CodeSegments struct {
+	Base   bool
+	Class  int64
+	Domain int64
+	Module int64
+	Reason int64
+}
+
+// SplitCode 拆分错误码
+func SplitCode(code int64) CodeSegments {
+	return CodeSegments{
+		Base:   code&baseCodeMask == baseCodeMask,
+		Class:  code & classMask,
+		Domain: (code & domainMask) >> domainShift,
+		Module: (code & moduleMask) >> moduleShift,
+		Reason: code & reasonMask,
+	}
+}
+
+// MatchCode 判断 code 是否属于 target 表示的错误码范围.
+//
+// target 中类别不为 0 时要求类别相同;领域、模块、原因码按层级匹配,
+// 以 target 中最深的非 0 分段为准,该分段及其上层分段都必须相同.
+// 例如 ErrorSystem 匹配所有系统错误, ErrorSystem|0x3 只匹配 ErrorSystem|0x3,
+// 模块的 Scope 匹配该模块下的所有错误码. 任意一方不是 base 错误码时要求完全相等.
+func MatchCode(code, target int64) bool {
+	src, dst := SplitCode(code), SplitCode(target)
+	if !src.Base || !dst.Base {
+		return code == target
+	}
+	if dst.Class != 0 && src.Class != dst.Class {
+		return false
+	}
+	switch {
+	case dst.Reason != 0:
+		return src.Domain == dst.Domain && src.Module == dst.Module && src.Reason == dst.Reason
+	case dst.Module != 0:
+		return src.Domain == dst.Domain && src.Module == dst.Module
+	case dst.Domain != 0:
+		return src.Domain == dst.Domain
+	default:
+		return true
+	}
+}
+
+// Specificity 返回错误码范围的具体程度,数值越大越具体,
+// 用于在多个匹配的范围中选择最具体的一个,例如映射表的查找
+func Specificity(target int64) int {
+	segments := SplitCode(target)
+	level := 0
+	if segments.Class != 0 {
+		level = 1
+	}
+	switch {
+	case segments.Reason != 0:
+		level += 8
+	case segments.Module != 0:
+		level += 6
+	case segments.Domain != 0:
+		level += 4
+	}
+	return level
+}
+
+// MatchError 判断 err 的错误码是否属于 target 表示的错误码范围
+func MatchError(err error, target int64) bool {
+	if e, ok := err.(Error); ok {
+		return MatchCode(e.GetCode(), target)
+	}
+	return false
+}
+
+var domains = struct {
+	sync.Mutex
+	byID map[int64]*Domain
+}{byID: make(map[int64]*Domain)}
+
+// Domain 错误码领域,通常对应一个服务或业务线
+type Domain struct {
+	name    string
+	id      int64
+	mutex   sync.Mutex
+	modules map[int64]*Module
+}
+
+// NewDomain 根据名称分配领域,领域编号由名称哈希得到,
+// 与已有领域冲突时 panic,此时使用 NewDomainWithID 指定编号
+func NewDomain(name string) *Domain {
+	return NewDomainWithID(name, hashID(name, maxDomainID))
+}
+
+// NewDomainWithID 使用指定编号分配领域,编号范围 1-524287,
+// 同一名称重复分配返回同一个领域,编号冲突时 panic
+func NewDomainWithID(name string, id int64) *Domain {
+	if id <= 0 || id > maxDomainID {
+		panic(fmt.Sprintf("领域 %s 的编号 %d 超出范围 1-%d", name, id, maxDomainID))
+	}
+	domains.Lock()
+	defer domains.Unlock()
+	if exist, ok := domains.byID[id]; ok {
+		if exist.name != name {
+			panic(fmt.Sprintf("领域 %s 与 %s 的编号 %d 冲突", name, exist.name, id))
+		}
+		return exist
+	}
+	domain := &Domain{name: name, id: id, modules: make(map[int64]*Module)}
+	domains.byID[id] = domain
+	return domain
+}
+
+// Name 领域名称
+func (d *Domain) Name() string {
+	return d.name
+}
+
+// Scope 返回领域的错误码范围,用于 MatchCode 匹配领域下的所有错误码
+func (d *Domain) Scope() int64 {
+	return _baseError | d.id<<domainShift
+}
+
+// Module 根据名称在领域下分配模块,编号冲突时 panic,此时使用 ModuleWithID 指定编号
+func (d *Domain) Module(name string) *Module {
+	return d.ModuleWithID(name, hashID(d.name+"/"+name, maxModuleID))
+}
+
+// ModuleWithID 使用指定编号分配模块,编号范围 1-4095
+func (d *Domain) ModuleWithID(name string, id int64) *Module {
+	if id <= 0 || id > maxModuleID {
+		panic(fmt.Sprintf("模块 %s.%s 的编号 %d 超出范围 1-%d", d.name, name, id, maxModuleID))
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if exist, ok := d.modules[id]; ok {
+		if exist.name != name {
+			panic(fmt.Sprintf("模块 %s.%s 与 %s.%s 的编号 %d 冲突", d.name, name, d.name, exist.name, id))
+		}
+		return exist
+	}
+	module := &Module{domain: d, name: name, id: id}
+	d.modules[id] = module
+	return module
+}
+
+// Module 错误码模块
+type Module struct {
+	domain *Domain
+	name   string
+	id     int64
+}
+
+// Name 模块名称,格式为 领域.模块
+func (m *Module) Name() string {
+	return m.domain.name + "." + m.name
+}
+
+// Scope 返回模块的错误码范围,用于 MatchCode 匹配模块下的所有错误码
+func (m *Module) Scope() int64 {
+	return m.domain.Scope() | m.id<<moduleShift
+}
+
+// Code 分配业务级别(ErrorMessage)的错误码,reason 范围 1-65535
+func (m *Module) Code(reason int64) int64 {
+	return m.code(ErrorMessage, reason)
+}
+
+// SystemCode 分配系统级别(ErrorSystem)的错误码,reason 范围 1-65535
+func (m *Module) SystemCode(reason int64) int64 {
+	return m.code(ErrorSystem, reason)
+}
+
+func (m *Module) code(class int64, reason int64) int64 {
+	if reason <= 0 || reason > maxReason {
+		panic(fmt.Sprintf("模块 %s 的原因码 %d 超出范围 1-%d", m.Name(), reason, maxReason))
+	}
+	return class | m.Scope() | reason
+}
+
+func hashID(name string, max int64) int64 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return int64(h.Sum32())%max + 1
+}
@@ -0,0 +1,141 @@
+package errors
+
+import (
+	"context"
+	errors1 "errors"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// TraceInfo 错误关联的请求链路信息
+type TraceInfo struct {
+	TraceID   string
+	SpanID    string
+	RequestID string
+}
+
+// IsZero 是否没有任何链路信息
+func (info TraceInfo) IsZero() bool {
+	return info == TraceInfo{}
+}
+
+// merge 使用 other 补充 info 中为空的字段
+func (info TraceInfo) merge(other TraceInfo) TraceInfo {
+	if info.TraceID == "" {
+		info.TraceID = other.TraceID
+	}
+	if info.SpanID == "" {
+		info.SpanID = other.SpanID
+	}
+	if info.RequestID == "" {
+		info.RequestID = other.RequestID
+	}
+	return info
+}
+
+func (info TraceInfo) fields() []zap.Field {
+	var fields []zap.Field
+	if info.TraceID != "" {
+		fields = append(fields, zap.String("traceId", info.TraceID))
+	}
+	if info.SpanID != "" {
+		fields = append(fields, zap.String("spanId", info.SpanID))
+	}
+	if info.RequestID != "" {
+		fields = append(fields, zap.String("requestId", info.RequestID))
+	}
+	return fields
+}
+
+// ContextExtractor 从 context 中提取链路信息,例如从 OpenTelemetry 的 span 或 gRPC metadata 中读取
+type ContextExtractor func(ctx context.Context) TraceInfo
+
+var extractors = struct {
+	sync.RWMutex
+	list []ContextExtractor
+}{list: []ContextExtractor{traceInfoFromContext}}
+
+// RegisterContextExtractor 注册链路信息提取器,后注册的优先,
+// 多个提取器的结果会合并,优先的提取器已经提取到的字段不会被覆盖,应在 init 中调用
+func RegisterContextExtractor(extractor ContextExtractor) {
+	if extractor == nil {
+		return
+	}
+	extractors.Lock()
+	defer extractors.Unlock()
+	extractors.list = append([]ContextExtractor{extractor}, extractors.list...)
+}
+
+// ExtractTraceInfo 使用注册的提取器从 context 中提取链路信息
+func ExtractTraceInfo(ctx context.Context) TraceInfo {
+	info := TraceInfo{}
+	if ctx == nil {
+		return info
+	}
+	extractors.RLock()
+	defer extractors.RUnlock()
+	for _, extractor := range extractors.list {
+		info = info.merge(extractor(ctx))
+	}
+	return info
+}
+
+type traceInfoKey struct{}
+
+// NewContext 返回携带链路信息的 context,默认的提取器会读取该信息,
+// 没有接入链路追踪时可以在请求入口设置 RequestID
+func NewContext(ctx context.Context, info TraceInfo) context.Context {
+	return context.WithValue(ctx, traceInfoKey{}, info.merge(traceInfoFromContext(ctx)))
+}
+
+func traceInfoFromContext(ctx context.Context) TraceInfo {
+	info, _ := ctx.Value(traceInfoKey{}).(TraceInfo)
+	return info
+}
+
+// WithContext 从 context 中提取链路信息附加到错误上,没有设置时从原因链中继承
+func WithContext(ctx context.Context) Option {
+	info := ExtractTraceInfo(ctx)
+	return func(opts *options) {
+		opts.trace = info
+	}
+}
+
+// FromContext 构建错误并附加 context 中的链路信息
+func FromContext(ctx context.Context, errorCode int64, message string, opts ...Option) Error {
+	return newError(errorCode, message, errors1.New(message), nil, append([]Option{WithContext(ctx)}, opts...))
+}
+
+// WrapContext 包装错误并附加 context 中的链路信息, err 为 nil 时返回 nil
+func WrapContext(ctx context.Context, errorCode int64, err error, opts ...Option) Error {
+	if err == nil {
+		return nil
+	}
+	return newError(errorCode, err.Error(), err, []error{err}, append([]Option{WithContext(ctx)}, opts...))
+}
+
+// TraceInfo 返回错误关联的链路信息
+func (err *baseError) TraceInfo() TraceInfo {
+	return err.trace
+}
+
+// GetTraceInfo 返回错误链中第一个关联的链路信息
+func GetTraceInfo(err error) TraceInfo {
+	var traced interface{ TraceInfo() TraceInfo }
+	if As(err, &traced) {
+		return traced.TraceInfo()
+	}
+	return TraceInfo{}
+}
+
+// applyTrace 根据选项设置链路信息,没有设置时从原因链中继承
+func (err *baseError) applyTrace(o *options) {
+	err.trace = o.trace
+	for _, cause := range err.causes {
+		if !err.trace.IsZero() {
+			return
+		}
+		err.trace = GetTraceInfo(cause)
+	}
+}
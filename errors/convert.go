@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"sync"
+)
+
+// Converter 将第三方错误转换为 Error,无法识别时返回 nil
+type Converter func(err error) Error
+
+var converters = struct {
+	sync.RWMutex
+	list []Converter
+}{}
+
+func init() {
+	RegisterConverter(convertNetError)
+	RegisterConverter(convertUnexpectedEOF)
+	RegisterConverter(convertNotExist)
+	RegisterConverter(convertContextError)
+}
+
+// RegisterConverter 注册 ConverError 使用的错误转换器,用于识别第三方驱动等返回的错误,
+// 后注册的转换器优先,应在 init 中调用
+func RegisterConverter(converter Converter) {
+	if converter == nil {
+		return
+	}
+	converters.Lock()
+	defer converters.Unlock()
+	converters.list = append([]Converter{converter}, converters.list...)
+}
+
+func convert(err error) Error {
+	converters.RLock()
+	defer converters.RUnlock()
+	for _, converter := range converters.list {
+		if e := converter(err); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func convertContextError(err error) Error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return WrappedError(ErrorSystemTimeout, err, WithTimeout())
+	case errors.Is(err, context.Canceled):
+		return WrappedError(ErrorSystemCanceled, err, WithRetryable(false))
+	}
+	return nil
+}
+
+func convertNotExist(err error) Error {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, fs.ErrNotExist) {
+		// 业务错误的消息会返回给调用方,不能使用驱动的原始消息
+		return WrappedError(ErrorMessageNotFount, err, WithPublicMessage("未找到"))
+	}
+	return nil
+}
+
+// convertUnexpectedEOF 连接中途断开, io.EOF 只表示数据读取结束,不是网络错误,按普通系统错误处理
+func convertUnexpectedEOF(err error) Error {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return WrappedError(ErrorSystemNet, err)
+	}
+	return nil
+}
+
+func convertNetError(err error) Error {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return WrappedError(ErrorSystemNet, err)
+	}
+	return nil
+}
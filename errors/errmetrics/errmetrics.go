@@ -0,0 +1,170 @@
+// Package errmetrics 按错误类别和错误码名称统计错误数量,并以 Prometheus 文本格式导出
+package errmetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/coffeehc/base/errors"
+)
+
+// MetricName 导出的指标名称
+const MetricName = "base_errors_total"
+
+// PrometheusContentType Prometheus 文本格式的 Content-Type
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Sample 一组标签的计数
+type Sample struct {
+	Event string
+	// Class 错误类别: system、message,不是 base 错误码时为 unknown
+	Class string
+	// Name 错误码注册的名称,未注册时为十六进制的错误码
+	Name  string
+	Count uint64
+}
+
+// Metrics 错误统计
+type Metrics interface {
+	// Observe 记录一次错误事件,可以作为 errors.Observer 使用
+	Observe(event errors.Event, err errors.Error)
+	// Snapshot 返回当前所有计数,按标签排序
+	Snapshot() []Sample
+	// WritePrometheus 以 Prometheus 文本格式输出
+	WritePrometheus(w io.Writer) error
+	// Reset 清空计数
+	Reset()
+}
+
+type sampleKey struct {
+	event string
+	class string
+	name  string
+}
+
+type metricsImpl struct {
+	mutex  sync.RWMutex
+	counts map[sampleKey]*atomic.Uint64
+}
+
+// New 创建错误统计,需要通过 errors.SetObserver(m.Observe) 启用,或者使用 Enable
+func New() Metrics {
+	return &metricsImpl{counts: make(map[sampleKey]*atomic.Uint64)}
+}
+
+var defaultMetrics = New()
+
+// Default 返回默认的错误统计
+func Default() Metrics {
+	return defaultMetrics
+}
+
+// Enable 将默认的错误统计设置为 errors 的观察者,返回默认的错误统计
+func Enable() Metrics {
+	errors.SetObserver(defaultMetrics.Observe)
+	return defaultMetrics
+}
+
+// Disable 关闭错误观察者
+func Disable() {
+	errors.SetObserver(nil)
+}
+
+// Handler 返回输出默认错误统计的 http.Handler,可以挂载到 /metrics
+func Handler() http.Handler {
+	return HandlerFor(defaultMetrics)
+}
+
+// HandlerFor 返回输出指定错误统计的 http.Handler
+func HandlerFor(m Metrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", PrometheusContentType)
+		_ = m.WritePrometheus(w)
+	})
+}
+
+func (impl *metricsImpl) Observe(event errors.Event, err errors.Error) {
+	if err == nil {
+		return
+	}
+	key := sampleKey{event: event.String(), class: className(err.GetCode()), name: codeName(err.GetCode())}
+	impl.mutex.RLock()
+	counter, ok := impl.counts[key]
+	impl.mutex.RUnlock()
+	if !ok {
+		impl.mutex.Lock()
+		if counter, ok = impl.counts[key]; !ok {
+			counter = &atomic.Uint64{}
+			impl.counts[key] = counter
+		}
+		impl.mutex.Unlock()
+	}
+	counter.Add(1)
+}
+
+func (impl *metricsImpl) Snapshot() []Sample {
+	impl.mutex.RLock()
+	samples := make([]Sample, 0, len(impl.counts))
+	for key, counter := range impl.counts {
+		samples = append(samples, Sample{Event: key.event, Class: key.class, Name: key.name, Count: counter.Load()})
+	}
+	impl.mutex.RUnlock()
+	sort.Slice(samples, func(i, j int) bool {
+		a, b := samples[i], samples[j]
+		if a.Event != b.Event {
+			return a.Event < b.Event
+		}
+		if a.Class != b.Class {
+			return a.Class < b.Class
+		}
+		return a.Name < b.Name
+	})
+	return samples
+}
+
+func (impl *metricsImpl) WritePrometheus(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "# HELP %s 按错误类别和错误码名称统计的错误数量\n", MetricName)
+	fmt.Fprintf(writer, "# TYPE %s counter\n", MetricName)
+	for _, sample := range impl.Snapshot() {
+		fmt.Fprintf(writer, "%s{event=\"%s\",class=\"%s\",name=\"%s\"} %d\n", MetricName,
+			escapeLabel(sample.Event), escapeLabel(sample.Class), escapeLabel(sample.Name), sample.Count)
+	}
+	return writer.Flush()
+}
+
+func (impl *metricsImpl) Reset() {
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+	impl.counts = make(map[sampleKey]*atomic.Uint64)
+}
+
+func className(code int64) string {
+	switch {
+	case errors.MatchCode(code, errors.ErrorSystem):
+		return "system"
+	case errors.MatchCode(code, errors.ErrorMessage):
+		return "message"
+	default:
+		return "unknown"
+	}
+}
+
+func codeName(code int64) string {
+	if name := errors.CodeName(code); name != "" {
+		return name
+	}
+	return fmt.Sprintf("0x%x", code)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,89 @@
+package errmetrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coffeehc/base/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestMetrics(t *testing.T) {
+	m := Enable()
+	defer Disable()
+	m.Reset()
+
+	_ = errors.SystemDBError("timeout").GetFields()
+	_ = errors.SystemDBError("timeout")
+	_ = errors.NotFountError("user")
+	_ = errors.NotFountError("user").WithField("userId", 1)
+	_ = errors.NewMultiError("batch") // 没有子错误,不统计
+	_ = errors.BuildError(errors.ErrorMessage|0x7f, "unregistered")
+
+	counts := make(map[string]uint64)
+	for _, sample := range m.Snapshot() {
+		counts[sample.Event+"/"+sample.Class+"/"+sample.Name] = sample.Count
+	}
+	want := map[string]uint64{
+		"created/system/SYSTEM_DB":          2,
+		"logged/system/SYSTEM_DB":           1,
+		"created/message/MESSAGE_NOT_FOUND": 2,
+		"created/message/0x1200007f":        1,
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("unexpected samples %v", counts)
+	}
+	for key, count := range want {
+		if counts[key] != count {
+			t.Fatalf("%s: want %d, got %d", key, count, counts[key])
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	if recorder.Header().Get("Content-Type") != PrometheusContentType ||
+		!strings.Contains(body, "# TYPE base_errors_total counter\n") ||
+		!strings.Contains(body, `base_errors_total{event="created",class="system",name="SYSTEM_DB"} 2`) {
+		t.Fatalf("unexpected prometheus output:\n%s", body)
+	}
+
+	Disable()
+	_ = errors.SystemDBError("timeout")
+	for _, sample := range m.Snapshot() {
+		if sample.Name == "SYSTEM_DB" && sample.Event == "created" && sample.Count != 2 {
+			t.Fatal("disabled observer should not count")
+		}
+	}
+}
+
+func TestMultiErrorLoggedOnce(t *testing.T) {
+	m := Enable()
+	defer Disable()
+	m.Reset()
+
+	multi := errors.NewMultiError("batch")
+	multi.Add(0, errors.SystemDBError("timeout"))
+	multi.Add(1, errors.SystemRedisError("timeout"))
+	fields := multi.ErrorOrNil().GetFieldsWithCause()
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	for i := 0; i < 2; i++ {
+		// 子错误的字段在编码时才展开,重复编码不应重复统计
+		buf, err := enc.EncodeEntry(zapcore.Entry{Message: "batch"}, fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Free()
+	}
+	logged := make(map[string]uint64)
+	for _, sample := range m.Snapshot() {
+		if sample.Event == "logged" {
+			logged[sample.Name] = sample.Count
+		}
+	}
+	if len(logged) != 1 || logged["SYSTEM"] != 1 {
+		t.Fatalf("one log line should count once for the parent only, got %v", logged)
+	}
+}
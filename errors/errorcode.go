@@ -9,6 +9,8 @@ const (
 	ErrorMessage = _baseError | 0x2000000
 
 	ErrorMessageNotFount = ErrorMessage | 0x1
+	// 参数校验失败,见 ValidationError
+	ErrorMessageValidation = ErrorMessage | 0x2
 
 	ErrorSystemInternal = ErrorSystem | 0x1
 	// ErrCodeScopeBaseRPC RPC级别的 ErrCode
@@ -19,10 +21,15 @@ const (
 	ErrorSystemRPC = ErrorSystem | 0x4
 
 	ErrorSystemNet = ErrorSystem | 0x5
+	// 超时,包括 context.DeadlineExceeded
+	ErrorSystemTimeout = ErrorSystem | 0x6
+	// 调用被取消,包括 context.Canceled
+	ErrorSystemCanceled = ErrorSystem | 0x7
 )
 
+// EqualError 判断 srcCode 是否属于 targetCode 表示的错误码范围,按层级匹配,见 MatchCode
 func EqualError(srcCode, targetCode int64) bool {
-	return srcCode&targetCode == targetCode
+	return MatchCode(srcCode, targetCode)
 }
 
 func IsBaseErrorCode(code int64) bool {
@@ -78,6 +85,20 @@ func IsRPCError(err error) bool {
 	return false
 }
 
+func IsTimeoutError(err error) bool {
+	if e, ok := err.(Error); ok {
+		return EqualError(e.GetCode(), ErrorSystemTimeout)
+	}
+	return false
+}
+
+func IsCanceledError(err error) bool {
+	if e, ok := err.(Error); ok {
+		return EqualError(e.GetCode(), ErrorSystemCanceled)
+	}
+	return false
+}
+
 func IsInternalError(err error) bool {
 	if e, ok := err.(Error); ok {
 		return EqualError(e.GetCode(), ErrorSystemInternal)
@@ -85,6 +106,13 @@ func IsInternalError(err error) bool {
 	return false
 }
 
+func IsValidationError(err error) bool {
+	if e, ok := err.(Error); ok {
+		return EqualError(e.GetCode(), ErrorMessageValidation)
+	}
+	return false
+}
+
 func IsNotFountError(err error) bool {
 	if e, ok := err.(Error); ok {
 		return EqualError(e.GetCode(), ErrorMessageNotFount)
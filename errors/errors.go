@@ -2,8 +2,12 @@ package errors
 
 import (
 	"encoding/json"
+	errors1 "errors"
+	"sort"
+	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Error 基础的错误接口
@@ -13,23 +17,89 @@ type Error interface {
 	GetFields(fields ...zap.Field) []zap.Field
 	GetFieldsWithCause(fields ...zap.Field) []zap.Field
 	FormatRPCError() string
-	Is(Error) bool
+	// Is 实现标准库 errors.Is 的约定, target 为 Error 时按错误码层级匹配,见 MatchCode
+	Is(target error) bool
 	ToError() error
+	// WithField 返回附加了指定元数据的新 Error,原 Error 不变
+	WithField(key string, value interface{}) Error
+	// WithFields 返回附加了多个元数据的新 Error,原 Error 不变
+	WithFields(fields map[string]interface{}) Error
+	// IsRetryable 错误是否可以重试
+	IsRetryable() bool
+	// IsTimeout 错误是否为超时
+	IsTimeout() bool
+	// RetryAfter 建议的重试间隔,0 表示没有建议
+	RetryAfter() time.Duration
 }
 
-// BaseError Error 接口的实现,可 json 序列化
+// BaseError Error 接口的实现,可 json 序列化,序列化格式见 jsonError
 type baseError struct {
-	Code    int64  `json:"code"`
-	Message string `json:"msg"`
-	e       error
+	Code int64
+	// Message 内部诊断消息,用于日志,不直接返回给调用方
+	Message       string
+	Details       map[string]interface{}
+	publicMessage string
+	messageKey    string
+	retryable     bool
+	// retrySet 是否通过 WithRetryable 显式设置了可否重试
+	retrySet   bool
+	timeout    bool
+	retryAfter time.Duration
+	// service 反序列化得到的错误的来源服务
+	service string
+	// remoteStack 反序列化得到的调用栈
+	remoteStack string
+	trace       TraceInfo
+	// sentinel NewSentinel 创建的哨兵错误,作为 errors.Is 的目标时按错误码范围匹配
+	sentinel bool
+	e        error
+	// causes 导致该错误的原因,可以有多个
+	causes []error
+	stack  []uintptr
 }
 
+// ToError 返回底层的 error,不会返回 nil
 func (err *baseError) ToError() error {
+	if err.e == nil {
+		return errors1.New(err.Message)
+	}
 	return err.e
 }
 
-func (err *baseError) Is(err2 Error) bool {
-	return err.Code == err2.GetCode()
+// Is 供标准库 errors.Is 使用, target 为 NewSentinel 创建的哨兵错误时错误码属于 target 的错误码范围即匹配,
+// 例如 errors.Is(err, ErrorClassSystem) 匹配所有系统错误, errors.Is(err, ErrNotFound) 匹配所有未找到错误;
+// target 为普通的 Error 时错误码和消息都相同才匹配
+func (err *baseError) Is(target error) bool {
+	if s, ok := target.(interface{ isSentinel() bool }); ok && s.isSentinel() {
+		return MatchCode(err.Code, target.(Error).GetCode())
+	}
+	t, ok := target.(Error)
+	if !ok {
+		return false
+	}
+	return err.Code == t.GetCode() && err.Error() == t.Error()
+}
+
+func (err *baseError) isSentinel() bool {
+	return err.sentinel
+}
+
+// Unwrap 返回所有原因,供标准库 errors.Is/errors.As 遍历错误链
+func (err *baseError) Unwrap() []error {
+	return err.causes
+}
+
+// Cause 返回第一个原因,没有原因时返回 nil
+func (err *baseError) Cause() error {
+	if len(err.causes) == 0 {
+		return nil
+	}
+	return err.causes[0]
+}
+
+// RootCause 沿第一个原因向下查找最底层的错误,没有原因时返回自身
+func (err *baseError) RootCause() error {
+	return RootCause(err)
 }
 
 func (err *baseError) FormatRPCError() string {
@@ -45,27 +115,129 @@ func (err *baseError) GetCode() int64 {
 	return err.Code
 }
 
-func (err *baseError) GetFields(fields ...zap.Field) []zap.Field {
-	if len(fields) == 0 {
-		return []zap.Field{zap.Int64("errCode", err.GetCode())}
+func (err *baseError) WithField(key string, value interface{}) Error {
+	return err.WithFields(map[string]interface{}{key: value})
+}
+
+func (err *baseError) WithFields(fields map[string]interface{}) Error {
+	clone := *err
+	clone.Details = make(map[string]interface{}, len(err.Details)+len(fields))
+	for k, v := range err.Details {
+		clone.Details[k] = v
+	}
+	for k, v := range fields {
+		clone.Details[k] = v
+	}
+	return &clone
+}
+
+// GetDetails 返回附加的元数据
+func (err *baseError) GetDetails() map[string]interface{} {
+	return err.Details
+}
+
+// detailFields 将元数据按 key 排序后转换为日志字段
+func (err *baseError) detailFields() []zap.Field {
+	if len(err.Details) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(err.Details))
+	for k := range err.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]zap.Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, zap.Any(k, err.Details[k]))
 	}
-	return append([]zap.Field{zap.Int64("errCode", err.GetCode())}, fields...)
+	return fields
 }
+
+// codeFields 输出错误码,已注册的错误码同时输出名称
+func (err *baseError) codeFields() []zap.Field {
+	if name := CodeName(err.Code); name != "" {
+		return []zap.Field{zap.Int64("errCode", err.Code), zap.String("errName", name)}
+	}
+	return []zap.Field{zap.Int64("errCode", err.Code)}
+}
+
+func (err *baseError) GetFields(fields ...zap.Field) []zap.Field {
+	notify(EventLogged, err)
+	return append(err.logFields(false), fields...)
+}
+
 func (err *baseError) GetFieldsWithCause(fields ...zap.Field) []zap.Field {
-	if len(fields) == 0 {
-		return []zap.Field{zap.Int64("errCode", err.GetCode()), zap.String("error", err.Message)}
+	notify(EventLogged, err)
+	return append(err.logFields(true), fields...)
+}
+
+// fieldBuilder 构建日志字段但不通知观察者,用于输出子错误,保证一条日志只统计一次
+type fieldBuilder interface {
+	logFields(withCause bool) []zap.Field
+}
+
+func (err *baseError) logFields(withCause bool) []zap.Field {
+	if !withCause {
+		errFields := append(err.codeFields(), err.trace.fields()...)
+		return append(errFields, err.detailFields()...)
+	}
+	errFields := append(err.codeFields(), zap.String("error", err.Message))
+	errFields = append(errFields, err.trace.fields()...)
+	errFields = append(errFields, err.detailFields()...)
+	if len(err.causes) > 0 {
+		errFields = append(errFields, zap.Array("causes", causeChain(err.causes)))
+	}
+	if stack := err.StackTrace(); stack != "" {
+		errFields = append(errFields, zap.String("errStack", stack))
 	}
-	return append([]zap.Field{zap.Int64("errCode", err.GetCode()), zap.String("error", err.Message)}, fields...)
+	return errFields
 }
 
-// ParseErrorFromJSON 从 Jons数据解析出 Error 对象
-func ParseErrorFromJSON(data []byte) Error {
-	err := &baseError{}
-	e := json.Unmarshal(data, err)
-	if e != nil {
-		return nil
+// causeChain 按深度优先展开整个原因链,用于日志输出
+type causeChain []error
+
+func (chain causeChain) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, cause := range chain {
+		if cause == nil {
+			continue
+		}
+		if err := enc.AppendObject(causeObject{cause}); err != nil {
+			return err
+		}
+		if err := chain.appendNested(enc, cause); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (chain causeChain) appendNested(enc zapcore.ArrayEncoder, cause error) error {
+	switch e := cause.(type) {
+	case interface{ Unwrap() []error }:
+		return causeChain(e.Unwrap()).MarshalLogArray(enc)
+	case interface{ Unwrap() error }:
+		if next := e.Unwrap(); next != nil {
+			return causeChain{next}.MarshalLogArray(enc)
+		}
+	}
+	return nil
+}
+
+type causeObject struct {
+	err error
+}
+
+func (cause causeObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if e, ok := cause.err.(Error); ok {
+		enc.AddInt64("errCode", e.GetCode())
 	}
-	return err
+	enc.AddString("error", cause.err.Error())
+	return nil
+}
+
+// ParseErrorFromJSON 从 json 数据解析出 Error 对象,与 ParseError 相同,无法解析时返回 ErrorSystemRPC
+func ParseErrorFromJSON(data []byte, causes ...error) Error {
+	return parseError(data, causes)
 }
 
 func ErrorToJson(err Error) string {
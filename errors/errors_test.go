@@ -0,0 +1,626 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCauseChain(t *testing.T) {
+	root := io.EOF
+	err := WrappedError(ErrorSystemNet, WrappedSystemError(root))
+	if !errors.Is(err, io.EOF) {
+		t.Fatal("errors.Is should see through wrapped errors")
+	}
+	var target Error
+	if !As(err.(*baseError).Cause(), &target) || target.GetCode() != ErrorSystem {
+		t.Fatal("cause should be the wrapped system error")
+	}
+	if RootCause(err) != io.EOF || err.(*baseError).RootCause() != io.EOF {
+		t.Fatal("root cause should be io.EOF")
+	}
+	if RootCause(io.EOF) != io.EOF {
+		t.Fatal("root cause of an error without cause is itself")
+	}
+}
+
+func TestJoinError(t *testing.T) {
+	if JoinError(ErrorSystem, nil, nil) != nil {
+		t.Fatal("join of nil errors should be nil")
+	}
+	e1, e2 := errors.New("e1"), MessageError("e2")
+	err := JoinError(ErrorSystem, e1, nil, e2)
+	if !errors.Is(err, e1) || !errors.Is(err, e2) {
+		t.Fatal("joined error should match all causes")
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range err.GetFieldsWithCause(zap.String("scope", "test")) {
+		field.AddTo(enc)
+	}
+	causes, ok := enc.Fields["causes"].([]interface{})
+	if !ok || len(causes) != 2 {
+		t.Fatalf("unexpected causes field: %v", enc.Fields["causes"])
+	}
+	if enc.Fields["scope"] != "test" {
+		t.Fatal("extra fields should be kept")
+	}
+}
+
+func TestStackCapture(t *testing.T) {
+	err := BuildError(ErrorSystem, "with stack", WithStack())
+	stack := err.(*baseError).StackTrace()
+	if !strings.HasPrefix(stack, packagePath+".TestStackCapture") {
+		t.Fatalf("stack should start at the caller, got:\n%s", stack)
+	}
+	if !strings.Contains(fmt.Sprintf("%+v", err), "TestStackCapture") {
+		t.Fatal("verbose format should render the stack")
+	}
+	if fmt.Sprintf("%v", err) != "with stack" {
+		t.Fatal("default format should only render the message")
+	}
+
+	SetStackPolicy(StackForSystemError)
+	defer SetStackPolicy(nil)
+	if SystemError("system").(*baseError).StackTrace() == "" {
+		t.Fatal("system errors should capture stack by policy")
+	}
+	if MessageError("message").(*baseError).StackTrace() != "" {
+		t.Fatal("message errors should not capture stack by policy")
+	}
+	if BuildError(ErrorSystem, "off", WithoutStack()).(*baseError).StackTrace() != "" {
+		t.Fatal("WithoutStack should override policy")
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	base := NotFountError("user not found")
+	err := base.WithField("userId", 123).WithFields(map[string]interface{}{"table": "users"})
+	if len(base.(*baseError).Details) != 0 {
+		t.Fatal("WithField must not modify the original error")
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range err.GetFields() {
+		field.AddTo(enc)
+	}
+	if enc.Fields["userId"] != int64(123) || enc.Fields["table"] != "users" {
+		t.Fatalf("unexpected fields: %v", enc.Fields)
+	}
+	parsed := ParseErrorFromJSON([]byte(err.FormatRPCError()))
+	details := parsed.(*baseError).Details
+	if parsed.GetCode() != ErrorMessageNotFount || details["table"] != "users" || details["userId"] != float64(123) {
+		t.Fatalf("details should survive json: %s", err.FormatRPCError())
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	if CodeName(ErrorSystemDB) != "SYSTEM_DB" {
+		t.Fatal("builtin codes should be registered")
+	}
+	RegisterCode(CodeInfo{Code: ErrorMessage | 0x100, Name: "TEST_REGISTRY", Message: "test message"})
+	if BuildErrorByCode(ErrorMessage|0x100).Error() != "test message" {
+		t.Fatal("BuildErrorByCode should use the registered message")
+	}
+	if info, ok := LookupCodeByName("TEST_REGISTRY"); !ok || info.Code != ErrorMessage|0x100 {
+		t.Fatal("code should be found by name")
+	}
+	assertPanic(t, func() { RegisterCode(CodeInfo{Code: ErrorMessage | 0x100, Name: "OTHER"}) })
+	assertPanic(t, func() { RegisterCode(CodeInfo{Code: ErrorMessage | 0x101, Name: "TEST_REGISTRY"}) })
+	if !strings.Contains(CatalogMarkdown(), "`TEST_REGISTRY`") {
+		t.Fatal("catalog should contain registered codes")
+	}
+}
+
+func assertPanic(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	fn()
+}
+
+func TestCodeSpace(t *testing.T) {
+	if EqualError(ErrorSystemRedis, ErrorSystemInternal) {
+		t.Fatal("overlapping reason bits must not match")
+	}
+	if !EqualError(ErrorSystemRedis, ErrorSystem) || !IsBaseErrorCode(ErrorMessageNotFount) {
+		t.Fatal("class matching should still work")
+	}
+	payment := NewDomain("test-order").Module("payment")
+	code := payment.Code(7)
+	if !MatchCode(code, ErrorMessage) || MatchCode(code, ErrorSystem) {
+		t.Fatal("structured code should keep its class")
+	}
+	if !MatchCode(code, payment.Scope()) || !MatchCode(code, NewDomain("test-order").Scope()) {
+		t.Fatal("structured code should match its module and domain")
+	}
+	if MatchCode(code, ErrorMessage|0x7) || MatchCode(payment.Code(8), code) {
+		t.Fatal("specific codes must match exactly")
+	}
+	if MatchCode(NewDomain("test-order").Module("refund").Code(7), payment.Scope()) {
+		t.Fatal("codes from other modules must not match")
+	}
+	segments := SplitCode(payment.SystemCode(9))
+	if !segments.Base || segments.Class != ErrorSystem&classMask || segments.Reason != 9 {
+		t.Fatalf("unexpected segments: %+v", segments)
+	}
+	assertPanic(t, func() { payment.Code(0) })
+	assertPanic(t, func() { NewDomainWithID("test-other", NewDomain("test-order").id) })
+}
+
+func TestPublicMessage(t *testing.T) {
+	err := WrappedError(ErrorSystemDB, errors.New("dial tcp 10.0.0.1:3306: connection refused"))
+	if PublicMessage(err) != "数据库异常" || strings.Contains(err.FormatRPCError(), "10.0.0.1") {
+		t.Fatalf("internal message must not leak: %s", err.FormatRPCError())
+	}
+	if PublicMessage(MessageError("余额不足")) != "余额不足" {
+		t.Fatal("business errors should be public by default")
+	}
+	custom := BuildError(ErrorMessage, "balance 10 < 20", WithPublicMessage("余额不足"), WithMessageKey("balance.lack"))
+	parsed := ParseErrorFromJSON([]byte(custom.FormatRPCError()))
+	if parsed.Error() != "余额不足" || MessageKey(parsed) != "balance.lack" {
+		t.Fatalf("unexpected parsed error: %s", custom.FormatRPCError())
+	}
+
+	SetDebugMode(true)
+	defer SetDebugMode(false)
+	parsed = ParseErrorFromJSON([]byte(err.FormatRPCError()))
+	if parsed.Error() != err.Error() || PublicMessage(parsed) != "数据库异常" {
+		t.Fatalf("debug mode should carry the internal message: %s", err.FormatRPCError())
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.yaml")
+	content := "en:\n  test.user.missing: \"user {{.userId}} not found\"\nzh-CN:\n  test.user.missing: \"用户 {{.userId}} 不存在\"\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadMessagesFile(path); err != nil {
+		t.Fatal(err)
+	}
+	err := BuildError(ErrorMessageNotFount, "user u1 missing", WithMessageKey("test.user.missing")).WithField("userId", "u1")
+	if Localize(err, "en-US") != "user u1 not found" || Localize(err, "fr") != "用户 u1 不存在" {
+		t.Fatalf("unexpected localized messages: %s, %s", Localize(err, "en-US"), Localize(err, "fr"))
+	}
+	if Localize(WrappedError(ErrorSystemDB, io.EOF), "en") != "Database error" {
+		t.Fatal("system errors should be localized by code name")
+	}
+	if Localize(MessageError("余额不足"), "en") != "余额不足" {
+		t.Fatal("messages without catalog entries should fall back to the public message")
+	}
+	if PreferredLanguage("fr;q=0.9, en-GB;q=0.8") != "en-GB" {
+		t.Fatal("preferred language should skip unsupported languages")
+	}
+}
+
+type testNetError struct {
+	timeout bool
+}
+
+func (e testNetError) Error() string   { return "net error" }
+func (e testNetError) Timeout() bool   { return e.timeout }
+func (e testNetError) Temporary() bool { return false }
+
+func TestRetryable(t *testing.T) {
+	err := NewNetError(testNetError{timeout: true})
+	if !err.IsTimeout() || !err.IsRetryable() {
+		t.Fatal("net timeout should be retryable")
+	}
+	wrapped := WrappedSystemError(err)
+	if !wrapped.IsTimeout() || !IsRetryable(ConverError(wrapped)) {
+		t.Fatal("retry semantics should be inherited when wrapping")
+	}
+	if NewNetError(testNetError{}).IsRetryable() {
+		t.Fatal("non temporary net errors should not be retryable")
+	}
+	hinted := BuildError(ErrorSystemRedis, "busy", WithRetryAfter(time.Second*2))
+	parsed := ParseErrorFromJSON([]byte(hinted.FormatRPCError()))
+	if !parsed.IsRetryable() || parsed.RetryAfter() != time.Second*2 {
+		t.Fatalf("retry semantics should survive json: %s", hinted.FormatRPCError())
+	}
+	if WrappedError(ErrorSystem, err, WithRetryable(false)).IsRetryable() {
+		t.Fatal("explicit option should override inherited semantics")
+	}
+}
+
+// restoreConverters 测试结束后恢复全局的错误转换器
+func restoreConverters(t *testing.T) {
+	converters.RLock()
+	saved := converters.list
+	converters.RUnlock()
+	t.Cleanup(func() {
+		converters.Lock()
+		defer converters.Unlock()
+		converters.list = saved
+	})
+}
+
+func TestConverError(t *testing.T) {
+	if err := ConverError(fmt.Errorf("query: %w", context.DeadlineExceeded)); !IsTimeoutError(err) || !err.IsTimeout() || !err.IsRetryable() {
+		t.Fatal("deadline exceeded should be a retryable timeout error")
+	}
+	if err := ConverError(context.Canceled); !IsCanceledError(err) || err.IsRetryable() {
+		t.Fatal("canceled should not be retryable")
+	}
+	if err := ConverError(sql.ErrNoRows); !IsNotFountError(err) || PublicMessage(err) != "未找到" {
+		t.Fatal("sql.ErrNoRows should be a not found error without leaking the driver message")
+	}
+	if _, e := os.Open(filepath.Join(t.TempDir(), "missing")); !IsNotFountError(ConverError(e)) {
+		t.Fatal("os.ErrNotExist should be a not found error")
+	}
+	if !IsNetError(ConverError(fmt.Errorf("read: %w", io.ErrUnexpectedEOF))) {
+		t.Fatal("io.ErrUnexpectedEOF should be a net error")
+	}
+	if err := ConverError(fmt.Errorf("decode body: %w", io.EOF)); err.GetCode() != ErrorSystem || err.IsRetryable() {
+		t.Fatal("io.EOF is the end of input, not a net error")
+	}
+	opErr := &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	if err := ConverError(opErr); !IsNetError(err) || !err.IsTimeout() {
+		t.Fatal("net.OpError should be a net error inheriting timeout")
+	}
+	if err := ConverError(errors.New("unknown")); err.GetCode() != ErrorSystem {
+		t.Fatal("unknown errors should be system errors")
+	}
+
+	restoreConverters(t)
+	driverErr := errors.New("duplicate key")
+	RegisterConverter(func(err error) Error {
+		if errors.Is(err, driverErr) {
+			return WrappedError(ErrorSystemDB, err)
+		}
+		return nil
+	})
+	if !IsDBError(ConverError(fmt.Errorf("insert: %w", driverErr))) {
+		t.Fatal("registered converter should be used")
+	}
+}
+
+func panicWith(value interface{}) {
+	panic(value)
+}
+
+func TestRecover(t *testing.T) {
+	call := func() (err error) {
+		defer Recover(&err)
+		panicWith("boom")
+		return nil
+	}
+	err := call()
+	var e Error
+	if !As(err, &e) || !IsInternalError(e) || e.Error() != "panic: boom" {
+		t.Fatalf("panic should be converted to internal error, got %v", err)
+	}
+	if stack := e.(*baseError).StackTrace(); !strings.HasPrefix(stack, "github.com/coffeehc/base/errors.panicWith") {
+		t.Fatalf("stack should start at the panic site, got %s", stack)
+	}
+
+	cause := errors.New("cause")
+	err = SafeCall(func() error {
+		panic(cause)
+	})
+	if !IsInternalError(err) || !errors.Is(err, cause) {
+		t.Fatal("SafeCall should keep the panic error as cause")
+	}
+	if SafeCall(func() error { return nil }) != nil {
+		t.Fatal("SafeCall without error should return nil")
+	}
+
+	done := make(chan struct{})
+	SafeGo(func() {
+		defer close(done)
+		var m map[string]int
+		m["x"] = 1
+	})
+	<-done
+
+	if e := ConverUnknowError(42); !IsInternalError(e) || e.Error() != "panic: 42" {
+		t.Fatal("ConverUnknowError should keep non-error values")
+	}
+}
+
+func TestWireRoundTrip(t *testing.T) {
+	SetServiceName("order")
+	defer SetServiceName("")
+	root := errors.New("connection reset")
+	inner := WrappedError(ErrorSystemDB, root).WithField("table", "orders")
+	err := WrappedError(ErrorSystemInternal, inner, WithPublicMessage("下单失败")).WithField("orderId", "o-1")
+
+	parsed := ParseError(err.FormatRPCError())
+	if parsed.GetCode() != ErrorSystemInternal || PublicMessage(parsed) != "下单失败" || GetDetails(parsed)["orderId"] != "o-1" {
+		t.Fatalf("top level should be restored, got %s", parsed.FormatRPCError())
+	}
+	if parsed.(*baseError).Service() != "order" || parsed.ToError() == nil {
+		t.Fatal("service and underlying error should be restored")
+	}
+	var db Error
+	if !As(Cause(parsed), &db) || !IsDBError(db) || GetDetails(db)["table"] != "orders" || db.(*baseError).Service() != "order" {
+		t.Fatal("cause chain should be restored")
+	}
+	if RootCause(parsed) == nil || IsBaseError(RootCause(parsed)) {
+		t.Fatal("plain root cause should be restored as a plain error")
+	}
+	if again := ParseError(parsed.FormatRPCError()); again.FormatRPCError() != parsed.FormatRPCError() {
+		t.Fatal("round trip should be stable")
+	}
+
+	for _, data := range []string{"not json", "{}", "null"} {
+		e := ParseError(data)
+		if e == nil || e.GetCode() != ErrorSystemRPC || MessageKey(e) != "base.error.parse" || e.ToError() == nil {
+			t.Fatalf("invalid data %q should return a usable parse error", data)
+		}
+		if ParseErrorFromJSON([]byte(data)).GetCode() != e.GetCode() {
+			t.Fatal("both parsers should agree")
+		}
+	}
+	legacy := ParseError(`{"code":268435457,"msg":"旧格式","details":{"a":1},"retryable":true}`)
+	if legacy.GetCode() != 268435457 || legacy.Error() != "旧格式" {
+		t.Fatal("legacy format without version should be parsed")
+	}
+	if len(GetDetails(legacy)) != 0 || legacy.IsRetryable() || PublicMessage(legacy) == "旧格式" {
+		t.Fatal("legacy format should only read code and msg as an internal message")
+	}
+	future := fmt.Sprintf(`{"v":%d,"code":268435457,"msg":"新格式"}`, WireVersion+1)
+	if e := ParseError(future); e.GetCode() != ErrorSystemRPC || MessageKey(e) != "base.error.parse" {
+		t.Fatalf("newer wire version should be rejected, got %v", e)
+	}
+	if json.Unmarshal([]byte(future), &baseError{}) == nil || json.Unmarshal([]byte(future), &MultiError{}) == nil {
+		t.Fatal("UnmarshalJSON should reject newer wire versions")
+	}
+	if FromProto(&ErrorBody{Version: WireVersion + 1, Code: ErrorSystem}).GetCode() != ErrorSystemRPC {
+		t.Fatal("FromProto should reject newer wire versions")
+	}
+}
+
+func TestProto(t *testing.T) {
+	SetServiceName("order")
+	defer SetServiceName("")
+	type item struct {
+		SKU string `json:"sku"`
+	}
+	inner := WrappedError(ErrorSystemDB, io.EOF).WithFields(map[string]interface{}{"item": item{SKU: "a"}, "count": 2})
+	err := WrappedError(ErrorSystemInternal, inner, WithRetryAfter(time.Second))
+
+	raw, e := proto.Marshal(ToProto(err))
+	if e != nil {
+		t.Fatal(e)
+	}
+	body := &ErrorBody{}
+	if e := proto.Unmarshal(raw, body); e != nil {
+		t.Fatal(e)
+	}
+	parsed := FromProto(body)
+	if parsed.GetCode() != ErrorSystemInternal || parsed.RetryAfter() != time.Second || parsed.(*baseError).Service() != "order" {
+		t.Fatal("top level should be restored")
+	}
+	if parsed.FormatRPCError() != err.FormatRPCError() {
+		t.Fatalf("protobuf and json formats should agree:\n%s\n%s", parsed.FormatRPCError(), err.FormatRPCError())
+	}
+	var db Error
+	if !As(Cause(parsed), &db) || !IsDBError(db) || GetDetails(db)["item"].(map[string]interface{})["sku"] != "a" {
+		t.Fatal("cause details should be restored")
+	}
+	if FromProto(nil) != nil || FromProto(&ErrorBody{}).GetCode() != ErrorSystemRPC {
+		t.Fatal("invalid bodies should be handled")
+	}
+	if ToProto(errors.New("plain")).GetCode() != ErrorSystem {
+		t.Fatal("plain errors should be converted")
+	}
+
+	SetDebugMode(true)
+	defer SetDebugMode(false)
+	stacked := BuildError(ErrorSystem, "boom", WithStack())
+	if stack := FromProto(ToProto(stacked)).(*baseError).StackTrace(); stack == "" || stack != stacked.(*baseError).StackTrace() {
+		t.Fatal("stack should be carried in debug mode")
+	}
+}
+
+func TestMultiError(t *testing.T) {
+	opts := make([]Option, 1, 2)
+	opts[0] = WithPublicMessage("导入失败")
+	_ = NewMultiError("批量导入失败", opts...)
+	if opts[:2][1] != nil {
+		t.Fatal("NewMultiError should not write into the caller's options")
+	}
+	multi := NewMultiError("批量导入失败")
+	if multi.ErrorOrNil() != nil {
+		t.Fatal("empty multi error should be nil")
+	}
+	multi.Add(0, MessageError("名称不能为空")).Add(1, nil).AddKey("sku-2", NotFountError("商品不存在"))
+	if multi.Len() != 2 || multi.GetCode() != ErrorMessage {
+		t.Fatalf("message children should derive ErrorMessage, got %x", multi.GetCode())
+	}
+	if multi.Error() != "批量导入失败: [0] 名称不能为空; [sku-2] 商品不存在" {
+		t.Fatalf("unexpected message %q", multi.Error())
+	}
+	multi.Add(3, io.EOF)
+	if multi.GetCode() != ErrorSystem || !errors.Is(multi, io.EOF) {
+		t.Fatal("system children should derive ErrorSystem and be reachable by errors.Is")
+	}
+	if NewMultiError("x").Add(0, MessageError("a")).Add(1, MessageError("b")).GetCode() != ErrorMessage {
+		t.Fatal("same code should be kept")
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range multi.WithField("batchId", "b-1").GetFields() {
+		field.AddTo(enc)
+	}
+	items, ok := enc.Fields["errors"].([]interface{})
+	if !ok || len(items) != 3 || enc.Fields["batchId"] != "b-1" {
+		t.Fatalf("all children should be logged, got %v", enc.Fields)
+	}
+	if first := items[0].(map[string]interface{}); first["index"] != 0 || first["error"] != "名称不能为空" {
+		t.Fatalf("unexpected item %v", first)
+	}
+
+	parsed, ok := ParseError(multi.FormatRPCError()).(*MultiError)
+	if !ok || parsed.Len() != 3 || parsed.GetCode() != ErrorSystem {
+		t.Fatal("multi error should round trip through json")
+	}
+	if item := parsed.Items()[1]; item.Index != -1 || item.Key != "sku-2" || !IsNotFountError(item.Err) {
+		t.Fatalf("keyed item should be restored, got %+v", item)
+	}
+	if fromProto, ok := FromProto(ToProto(multi)).(*MultiError); !ok || fromProto.FormatRPCError() != multi.FormatRPCError() {
+		t.Fatal("multi error should round trip through protobuf")
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	opts := make([]Option, 1, 2)
+	opts[0] = WithPublicMessage("校验失败")
+	_ = NewValidationError("", opts...)
+	if opts[:2][1] != nil {
+		t.Fatal("NewValidationError should not write into the caller's options")
+	}
+	v := NewValidationError("")
+	if v.ErrorOrNil() != nil {
+		t.Fatal("empty validation error should be nil")
+	}
+	v.Add("name", "required", "名称不能为空").Add("items[0].count", "min", "数量至少为 1")
+	if !IsValidationError(v) || !IsMessageError(v) || PublicMessage(v) != "参数校验失败" {
+		t.Fatal("validation error should be a message error")
+	}
+	if v.Error() != "参数校验失败: name 名称不能为空; items[0].count 数量至少为 1" {
+		t.Fatalf("unexpected message %q", v.Error())
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range v.GetFields() {
+		field.AddTo(enc)
+	}
+	list, ok := enc.Fields["violations"].([]interface{})
+	if !ok || len(list) != 2 || list[0].(map[string]interface{})["rule"] != "required" {
+		t.Fatalf("violations should be logged, got %v", enc.Fields)
+	}
+
+	raw := v.WithField("form", "order").FormatRPCError()
+	if !strings.Contains(raw, `"violations":[{"field":"name","rule":"required","msg":"名称不能为空"}`) {
+		t.Fatalf("violations should be serialized, got %s", raw)
+	}
+	parsed, ok := ParseError(raw).(*ValidationError)
+	if !ok || len(parsed.Violations()) != 2 || GetDetails(parsed)["form"] != "order" {
+		t.Fatal("validation error should round trip through json")
+	}
+	if fromProto, ok := FromProto(ToProto(v)).(*ValidationError); !ok || fromProto.Violations()[1] != v.Violations()[1] {
+		t.Fatal("validation error should round trip through protobuf")
+	}
+}
+
+func TestSentinel(t *testing.T) {
+	err := fmt.Errorf("load user: %w", NotFountError("用户不存在"))
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, ErrorClassMessage) || errors.Is(err, ErrorClassSystem) {
+		t.Fatal("not found errors should match ErrNotFound and the message class")
+	}
+	db := WrappedError(ErrorSystemDB, io.EOF)
+	if !errors.Is(db, ErrDB) || !errors.Is(db, ErrorClassSystem) || errors.Is(db, ErrRedis) || !errors.Is(db, io.EOF) {
+		t.Fatal("db errors should match by code hierarchy and keep the cause chain")
+	}
+	if !errors.Is(ConverError(context.DeadlineExceeded), ErrTimeout) {
+		t.Fatal("converted deadline should match ErrTimeout")
+	}
+	multi := NewMultiError("batch").Add(0, NotFountError("a")).Add(1, SystemDBError("b"))
+	if !errors.Is(multi, ErrNotFound) || !errors.Is(multi, ErrDB) {
+		t.Fatal("multi error should match its children")
+	}
+	var target *MultiError
+	if !errors.As(fmt.Errorf("wrap: %w", multi), &target) || target.Len() != 2 {
+		t.Fatal("errors.As should find the multi error")
+	}
+
+	module := NewDomain("sentinel").Module("user")
+	scope := NewSentinel(module.Scope(), "用户模块错误")
+	if !errors.Is(BuildError(module.Code(3), "x"), scope) || errors.Is(NotFountError("x"), scope) {
+		t.Fatal("module scope sentinel should match errors of the module only")
+	}
+	errBalance := MessageError("余额不足")
+	if errors.Is(MessageError("参数错误"), errBalance) || errors.Is(NotFountError("user"), errBalance) {
+		t.Fatal("distinct message errors should not match each other")
+	}
+	if errors.Is(SystemDBError("dup key"), SystemError("磁盘已满")) {
+		t.Fatal("ordinary errors with a class code should not act as wildcards")
+	}
+	if !errors.Is(fmt.Errorf("pay: %w", errBalance), errBalance) || !errors.Is(ParseError(errBalance.FormatRPCError()), errBalance) {
+		t.Fatal("ordinary errors should match the same error and its deserialized copy")
+	}
+	if ErrNotFound.ToError() == nil || PublicMessage(ErrNotFound) != "未找到" {
+		t.Fatal("sentinels should be usable as errors")
+	}
+}
+
+// restoreContextExtractors 测试结束后恢复全局的链路信息提取器
+func restoreContextExtractors(t *testing.T) {
+	extractors.RLock()
+	saved := extractors.list
+	extractors.RUnlock()
+	t.Cleanup(func() {
+		extractors.Lock()
+		defer extractors.Unlock()
+		extractors.list = saved
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	restoreContextExtractors(t)
+	ctx := NewContext(context.Background(), TraceInfo{RequestID: "req-1"})
+	type spanKey struct{}
+	RegisterContextExtractor(func(ctx context.Context) TraceInfo {
+		span, _ := ctx.Value(spanKey{}).(string)
+		if span == "" {
+			return TraceInfo{}
+		}
+		return TraceInfo{TraceID: "trace-1", SpanID: span, RequestID: "req-from-extractor"}
+	})
+	ctx = context.WithValue(ctx, spanKey{}, "span-1")
+
+	// 后注册的提取器优先,覆盖默认提取器从 NewContext 读取的 req-1
+	err := FromContext(ctx, ErrorSystemDB, "查询失败")
+	want := TraceInfo{TraceID: "trace-1", SpanID: "span-1", RequestID: "req-from-extractor"}
+	if GetTraceInfo(err) != want {
+		t.Fatalf("unexpected trace info %+v", GetTraceInfo(err))
+	}
+	if info := ExtractTraceInfo(NewContext(context.Background(), TraceInfo{RequestID: "req-2"})); info.RequestID != "req-2" || info.TraceID != "" {
+		t.Fatalf("default extractor should read NewContext, got %+v", info)
+	}
+
+	wrapped := WrappedError(ErrorSystemInternal, fmt.Errorf("load: %w", err))
+	if GetTraceInfo(wrapped) != want {
+		t.Fatal("wrapped errors should inherit the trace info")
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range wrapped.GetFields() {
+		field.AddTo(enc)
+	}
+	if enc.Fields["traceId"] != "trace-1" || enc.Fields["spanId"] != "span-1" || enc.Fields["requestId"] != "req-from-extractor" {
+		t.Fatalf("trace info should be logged, got %v", enc.Fields)
+	}
+
+	// 普通 error 的原因序列化后只保留消息,这里直接包装以检查原因链
+	raw := WrappedError(ErrorSystemInternal, err).FormatRPCError()
+	if strings.Count(raw, `"trace_id":"trace-1"`) != 1 {
+		t.Fatalf("trace info should be serialized once, got %s", raw)
+	}
+	parsed := ParseError(raw)
+	if GetTraceInfo(parsed) != want || GetTraceInfo(Cause(parsed)) != want {
+		t.Fatal("trace info should round trip through json")
+	}
+	if GetTraceInfo(FromProto(ToProto(wrapped))) != want {
+		t.Fatal("trace info should round trip through protobuf")
+	}
+	if WrapContext(ctx, ErrorSystem, nil) != nil || GetTraceInfo(WrapContext(ctx, ErrorSystem, io.EOF)) != want {
+		t.Fatal("WrapContext should attach the trace info")
+	}
+}
@@ -0,0 +1,86 @@
+package grpcerr
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/coffeehc/base/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryServerInterceptor 将服务端返回的错误转换为携带 ErrorInfo 的 gRPC status,
+// 消息语言根据请求元数据 accept-language 选择
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ToLocalizedStatus(err, requestLanguage(ctx)).Err()
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor 将流式服务端返回的错误转换为携带 ErrorInfo 的 gRPC status
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return ToLocalizedStatus(err, requestLanguage(ss.Context())).Err()
+		}
+		return nil
+	}
+}
+
+// requestLanguage 从请求的 accept-language 元数据中选择消息语言
+func requestLanguage(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return errors.DefaultLanguage
+	}
+	return errors.PreferredLanguage(strings.Join(md.Get("accept-language"), ","))
+}
+
+// UnaryClientInterceptor 将调用返回的 gRPC status 转换为 errors.Error
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return FromError(err)
+		}
+		return nil
+	}
+}
+
+// StreamClientInterceptor 将流式调用返回的 gRPC status 转换为 errors.Error, io.EOF 保持不变
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, FromError(err)
+		}
+		return &clientStream{ClientStream: stream}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return convertStreamError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return convertStreamError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return convertStreamError(s.ClientStream.CloseSend())
+}
+
+func convertStreamError(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	return FromError(err)
+}
@@ -0,0 +1,231 @@
+// Package grpcerr 在 errors.Error 与 gRPC status 之间相互转换
+package grpcerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/coffeehc/base/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorInfoDomain 携带 errors.Error 的 ErrorInfo 使用的 Domain
+const ErrorInfoDomain = "github.com/coffeehc/base/errors"
+
+const (
+	metadataCode      = "code"
+	metadataError     = "error"
+	metadataTraceID   = "trace_id"
+	metadataSpanID    = "span_id"
+	metadataRequestID = "request_id"
+)
+
+type codeMapping struct {
+	target int64
+	code   codes.Code
+}
+
+var mappings = struct {
+	sync.RWMutex
+	list []codeMapping
+}{
+	list: []codeMapping{
+		{target: errors.ErrorSystem, code: codes.Internal},
+		{target: errors.ErrorSystemInternal, code: codes.Internal},
+		{target: errors.ErrorSystemDB, code: codes.Internal},
+		{target: errors.ErrorSystemRedis, code: codes.Internal},
+		{target: errors.ErrorSystemRPC, code: codes.Unavailable},
+		{target: errors.ErrorSystemNet, code: codes.Unavailable},
+		{target: errors.ErrorSystemTimeout, code: codes.DeadlineExceeded},
+		{target: errors.ErrorSystemCanceled, code: codes.Canceled},
+		{target: errors.ErrorMessage, code: codes.InvalidArgument},
+		{target: errors.ErrorMessageNotFount, code: codes.NotFound},
+	},
+}
+
+// SetCodeMapping 设置错误码范围到 gRPC 状态码的映射, target 可以是错误码、类别或
+// 领域/模块的 Scope,转换时使用匹配的最具体的映射,同样具体时后设置的优先
+func SetCodeMapping(target int64, code codes.Code) {
+	mappings.Lock()
+	defer mappings.Unlock()
+	mappings.list = append(mappings.list, codeMapping{target: target, code: code})
+}
+
+// ToGRPCCode 将错误码转换为 gRPC 状态码
+func ToGRPCCode(code int64) codes.Code {
+	mappings.RLock()
+	defer mappings.RUnlock()
+	result, best := codes.Unknown, -1
+	for _, mapping := range mappings.list {
+		if !errors.MatchCode(code, mapping.target) {
+			continue
+		}
+		if level := errors.Specificity(mapping.target); level >= best {
+			result, best = mapping.code, level
+		}
+	}
+	return result
+}
+
+// FromGRPCCode 将不携带 ErrorInfo 的 gRPC 状态码转换为错误码
+func FromGRPCCode(code codes.Code) int64 {
+	switch code {
+	case codes.NotFound:
+		return errors.ErrorMessageNotFount
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.AlreadyExists, codes.OutOfRange,
+		codes.PermissionDenied, codes.Unauthenticated:
+		return errors.ErrorMessage
+	case codes.Unavailable:
+		return errors.ErrorSystemNet
+	case codes.DeadlineExceeded:
+		return errors.ErrorSystemTimeout
+	case codes.Canceled:
+		return errors.ErrorSystemCanceled
+	case codes.Aborted, codes.ResourceExhausted:
+		return errors.ErrorSystem
+	default:
+		return errors.ErrorSystemRPC
+	}
+}
+
+// ToStatus 将错误转换为 gRPC status,消息使用 errors.DefaultLanguage,见 ToLocalizedStatus
+func ToStatus(err error) *status.Status {
+	return ToLocalizedStatus(err, errors.DefaultLanguage)
+}
+
+// ToLocalizedStatus 将错误转换为 gRPC status,错误本身已经是 status 时直接返回,
+// status 的消息为指定语言的对外消息, errors.Error 的序列化信息保存在 ErrorInfo 中,
+// 同时附带 LocalizedMessage
+func ToLocalizedStatus(err error, lang string) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(errors.Error); !ok {
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+	}
+	e := errors.ConverError(err)
+	message := errors.Localize(e, lang)
+	st := status.New(ToGRPCCode(e.GetCode()), message)
+	reason := errors.CodeName(e.GetCode())
+	if reason == "" {
+		reason = fmt.Sprintf("0x%x", e.GetCode())
+	}
+	info := &errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: ErrorInfoDomain,
+		Metadata: map[string]string{
+			metadataCode:  strconv.FormatInt(e.GetCode(), 10),
+			metadataError: e.FormatRPCError(),
+		},
+	}
+	trace := errors.GetTraceInfo(e)
+	for key, value := range map[string]string{
+		metadataTraceID:   trace.TraceID,
+		metadataSpanID:    trace.SpanID,
+		metadataRequestID: trace.RequestID,
+	} {
+		if value != "" {
+			info.Metadata[key] = value
+		}
+	}
+	for key, value := range detailsOf(e) {
+		if _, ok := info.Metadata[key]; !ok {
+			info.Metadata[key] = value
+		}
+	}
+	details := []protoadapt.MessageV1{info, &errdetails.LocalizedMessage{Locale: lang, Message: message}}
+	if v, ok := e.(*errors.ValidationError); ok {
+		details = append(details, badRequest(v))
+	}
+	if withDetails, e := st.WithDetails(details...); e == nil {
+		return withDetails
+	}
+	return st
+}
+
+// badRequest 将字段校验失败信息转换为 BadRequest,方便非 Go 的调用方读取
+func badRequest(v *errors.ValidationError) *errdetails.BadRequest {
+	request := &errdetails.BadRequest{}
+	for _, violation := range v.Violations() {
+		request.FieldViolations = append(request.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Message,
+		})
+	}
+	return request
+}
+
+// detailsOf 将 Error 的元数据转换为字符串,方便非 Go 的调用方读取
+func detailsOf(err errors.Error) map[string]string {
+	details := errors.GetDetails(err)
+	if len(details) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(details))
+	for key, value := range details {
+		switch v := value.(type) {
+		case string:
+			result[key] = v
+		default:
+			raw, _ := json.Marshal(v)
+			result[key] = string(raw)
+		}
+	}
+	return result
+}
+
+// FromStatus 将 gRPC status 转换为 errors.Error, status 为 OK 时返回 nil,
+// 原始的 status 错误作为原因保留, status.Code、status.FromError 仍然可以得到原始的状态
+func FromStatus(st *status.Status) errors.Error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	cause := st.Err()
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != ErrorInfoDomain {
+			continue
+		}
+		if data, ok := info.GetMetadata()[metadataError]; ok {
+			return errors.ParseError(data, cause)
+		}
+		if code, e := strconv.ParseInt(info.GetMetadata()[metadataCode], 10, 64); e == nil {
+			return errors.BuildError(code, st.Message(), errors.WithCause(cause))
+		}
+	}
+	opts := append(retryOptions(st.Code()), errors.WithCause(cause))
+	return errors.BuildError(FromGRPCCode(st.Code()), st.Message(), opts...)
+}
+
+// retryOptions 不携带 ErrorInfo 的 status 根据状态码判断是否可以重试
+func retryOptions(code codes.Code) []errors.Option {
+	switch code {
+	case codes.DeadlineExceeded:
+		return []errors.Option{errors.WithTimeout()}
+	case codes.Unavailable, codes.Aborted, codes.ResourceExhausted:
+		return []errors.Option{errors.WithRetryable(true)}
+	default:
+		return nil
+	}
+}
+
+// FromError 将 gRPC 调用返回的错误转换为 errors.Error
+func FromError(err error) errors.Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(errors.Error); ok {
+		return e
+	}
+	if st, ok := status.FromError(err); ok {
+		return FromStatus(st)
+	}
+	return errors.ConverError(err)
+}
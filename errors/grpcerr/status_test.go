@@ -0,0 +1,101 @@
+package grpcerr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coffeehc/base/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusRoundTrip(t *testing.T) {
+	err := errors.NotFountError("user not found").WithField("userId", "u1")
+	st := ToStatus(err)
+	if st.Code() != codes.NotFound || st.Message() != "user not found" {
+		t.Fatalf("unexpected status: %v", st)
+	}
+	back := FromError(st.Err())
+	if back.GetCode() != errors.ErrorMessageNotFount || back.Error() != "user not found" {
+		t.Fatalf("unexpected error: %v", back)
+	}
+	if errors.GetDetails(back)["userId"] != "u1" {
+		t.Fatal("details should survive the status conversion")
+	}
+
+	v := errors.NewValidationError("").Add("name", "required", "名称不能为空")
+	st = ToStatus(v)
+	var request *errdetails.BadRequest
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.BadRequest); ok {
+			request = d
+		}
+	}
+	if st.Code() != codes.InvalidArgument || request == nil || request.GetFieldViolations()[0].GetField() != "name" {
+		t.Fatalf("validation errors should carry BadRequest, got %v", st.Details())
+	}
+	if _, ok := FromStatus(st).(*errors.ValidationError); !ok {
+		t.Fatal("validation error should survive the status conversion")
+	}
+
+	ctx := errors.NewContext(context.Background(), errors.TraceInfo{TraceID: "t1", RequestID: "r1"})
+	st = ToStatus(errors.FromContext(ctx, errors.ErrorSystemDB, "query failed"))
+	info := st.Details()[0].(*errdetails.ErrorInfo)
+	if info.GetMetadata()["trace_id"] != "t1" || info.GetMetadata()["request_id"] != "r1" {
+		t.Fatalf("trace info should be exposed in ErrorInfo metadata, got %v", info.GetMetadata())
+	}
+	if errors.GetTraceInfo(FromStatus(st)).RequestID != "r1" {
+		t.Fatal("trace info should survive the status conversion")
+	}
+}
+
+func TestCodeMapping(t *testing.T) {
+	if ToGRPCCode(errors.ErrorSystemDB) != codes.Internal || ToGRPCCode(errors.ErrorSystemNet) != codes.Unavailable {
+		t.Fatal("unexpected builtin mapping")
+	}
+	module := errors.NewDomain("grpcerr-test").Module("auth")
+	SetCodeMapping(module.Scope(), codes.PermissionDenied)
+	if ToGRPCCode(module.Code(1)) != codes.PermissionDenied {
+		t.Fatal("module mapping should be more specific than the class mapping")
+	}
+	if FromError(status.Error(codes.Unavailable, "down")).GetCode() != errors.ErrorSystemNet {
+		t.Fatal("plain status should be mapped by grpc code")
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.MessageError("bad request")
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if FromError(err).GetCode() != errors.ErrorMessage {
+		t.Fatal("client side should restore the error code")
+	}
+}
+
+func TestUnaryClientInterceptorKeepsStatus(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+	for _, origin := range []error{
+		ToStatus(errors.NotFountError("user not found")).Err(),
+		status.Error(codes.PermissionDenied, "denied"),
+	} {
+		err := interceptor(context.Background(), "/svc/Method", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return origin
+		})
+		if _, ok := err.(errors.Error); !ok {
+			t.Fatalf("expected errors.Error, got %T", err)
+		}
+		if status.Code(err) != status.Code(origin) {
+			t.Fatalf("expected %s, got %s", status.Code(origin), status.Code(err))
+		}
+		st, ok := status.FromError(err)
+		if !ok || len(st.Details()) != len(status.Convert(origin).Details()) {
+			t.Fatalf("status should be preserved: %v", st)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Package httperr 将 errors.Error 映射为 HTTP 状态码和 RFC 7807 problem+json 响应
+package httperr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coffeehc/base/errors"
+)
+
+// ContentType RFC 7807 定义的错误响应类型
+const ContentType = "application/problem+json"
+
+// TypePrefix problem 的 type 前缀,已注册的错误码使用 TypePrefix + 名称
+const TypePrefix = "urn:coffeehc:error:"
+
+const maxProblemSize = 1 << 20
+
+type statusMapping struct {
+	target int64
+	status int
+}
+
+var mappings = struct {
+	sync.RWMutex
+	list []statusMapping
+}{
+	list: []statusMapping{
+		{target: errors.ErrorSystem, status: http.StatusInternalServerError},
+		{target: errors.ErrorSystemRPC, status: http.StatusBadGateway},
+		{target: errors.ErrorSystemNet, status: http.StatusServiceUnavailable},
+		{target: errors.ErrorSystemTimeout, status: http.StatusGatewayTimeout},
+		{target: errors.ErrorMessage, status: http.StatusBadRequest},
+		{target: errors.ErrorMessageNotFount, status: http.StatusNotFound},
+	},
+}
+
+// SetStatusMapping 设置错误码范围到 HTTP 状态码的映射, target 可以是错误码、类别或
+// 领域/模块的 Scope,转换时使用匹配的最具体的映射,同样具体时后设置的优先
+func SetStatusMapping(target int64, status int) {
+	mappings.Lock()
+	defer mappings.Unlock()
+	mappings.list = append(mappings.list, statusMapping{target: target, status: status})
+}
+
+// StatusCode 将错误码转换为 HTTP 状态码,没有匹配的映射时返回 500
+func StatusCode(code int64) int {
+	mappings.RLock()
+	defer mappings.RUnlock()
+	result, best := http.StatusInternalServerError, -1
+	for _, mapping := range mappings.list {
+		if !errors.MatchCode(code, mapping.target) {
+			continue
+		}
+		if level := errors.Specificity(mapping.target); level >= best {
+			result, best = mapping.status, level
+		}
+	}
+	return result
+}
+
+// FromStatusCode 将不是 problem+json 的错误响应状态码转换为错误码
+func FromStatusCode(status int) int64 {
+	switch {
+	case status == http.StatusNotFound:
+		return errors.ErrorMessageNotFount
+	case status == http.StatusBadGateway:
+		return errors.ErrorSystemRPC
+	case status == http.StatusServiceUnavailable:
+		return errors.ErrorSystemNet
+	case status == http.StatusGatewayTimeout:
+		return errors.ErrorSystemTimeout
+	case status >= 400 && status < 500:
+		return errors.ErrorMessage
+	default:
+		return errors.ErrorSystem
+	}
+}
+
+// Problem 构建 RFC 7807 的错误内容,在 FormatRPCError 的字段基础上
+// 增加 type、title、status 以及指定语言的 detail
+func Problem(err error, lang string) (int, map[string]interface{}) {
+	e := errors.ConverError(err)
+	problem := make(map[string]interface{})
+	_ = json.Unmarshal([]byte(e.FormatRPCError()), &problem)
+	status := StatusCode(e.GetCode())
+	problemType := "about:blank"
+	if name := errors.CodeName(e.GetCode()); name != "" {
+		problemType = TypePrefix + name
+	}
+	problem["type"] = problemType
+	problem["title"] = http.StatusText(status)
+	problem["status"] = status
+	problem["detail"] = errors.Localize(e, lang)
+	return status, problem
+}
+
+// WriteError 将错误以 application/problem+json 写入响应,消息使用 errors.DefaultLanguage
+func WriteError(w http.ResponseWriter, err error) {
+	writeError(w, err, errors.DefaultLanguage)
+}
+
+// WriteLocalizedError 根据请求的 Accept-Language 选择消息语言,将错误写入响应
+func WriteLocalizedError(w http.ResponseWriter, r *http.Request, err error) {
+	writeError(w, err, errors.PreferredLanguage(r.Header.Get("Accept-Language")))
+}
+
+func writeError(w http.ResponseWriter, err error, lang string) {
+	if err == nil {
+		return
+	}
+	status, problem := Problem(err, lang)
+	data, _ := json.Marshal(problem)
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Language", lang)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if d := errors.RetryAfter(err); d > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10))
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// ParseProblem 从错误响应解析出 errors.Error,响应体不是本库生成时根据状态码构建错误
+func ParseProblem(status int, body []byte) errors.Error {
+	return parseProblem(status, body, retryOptions(status))
+}
+
+// parseProblem opts 只用于响应体不是本库生成的情况,本库生成的响应体已经包含重试信息
+func parseProblem(status int, body []byte, opts []errors.Option) errors.Error {
+	problem := struct {
+		Version int    `json:"v"`
+		Type    string `json:"type"`
+		Code    *int64 `json:"code"`
+		Title   string `json:"title"`
+		Detail  string `json:"detail"`
+		Message string `json:"message"`
+	}{}
+	if e := json.Unmarshal(body, &problem); e != nil {
+		message := strings.TrimSpace(string(body))
+		if message == "" {
+			message = http.StatusText(status)
+		}
+		return errors.BuildError(FromStatusCode(status), message, opts...)
+	}
+	// 第三方服务的响应体也可能包含 code,只有带版本号或本库 type 的响应体才按本库格式解析
+	if problem.Code != nil && (problem.Version > 0 || strings.HasPrefix(problem.Type, TypePrefix)) {
+		return errors.ParseError(string(body))
+	}
+	message := problem.Detail
+	if message == "" {
+		message = problem.Message
+	}
+	if message == "" {
+		message = problem.Title
+	}
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return errors.BuildError(FromStatusCode(status), message, opts...)
+}
+
+// retryOptions 不是本库生成的错误响应根据状态码判断是否可以重试
+func retryOptions(status int) []errors.Option {
+	switch status {
+	case http.StatusGatewayTimeout:
+		return []errors.Option{errors.WithTimeout()}
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusBadGateway:
+		return []errors.Option{errors.WithRetryable(true)}
+	default:
+		return nil
+	}
+}
+
+// ParseResponse 从 HTTP 响应解析出 errors.Error,状态码小于 400 时返回 nil,
+// 响应体会被读取但不会关闭
+func ParseResponse(resp *http.Response) errors.Error {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProblemSize))
+	if err != nil {
+		return errors.WrappedError(errors.ErrorSystemNet, fmt.Errorf("读取错误响应失败: %w", err))
+	}
+	opts := retryOptions(resp.StatusCode)
+	if seconds, err := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64); err == nil && seconds > 0 {
+		opts = append(opts, errors.WithRetryAfter(time.Duration(seconds)*time.Second))
+	}
+	return parseProblem(resp.StatusCode, body, opts)
+}
@@ -0,0 +1,57 @@
+package httperr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coffeehc/base/errors"
+)
+
+func TestWriteAndParse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, errors.NotFountError("order not found").WithField("orderId", "o1"))
+	}))
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || resp.Header.Get("Content-Type") != ContentType {
+		t.Fatalf("unexpected response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	e := ParseResponse(resp)
+	if e.GetCode() != errors.ErrorMessageNotFount || e.Error() != "order not found" || errors.GetDetails(e)["orderId"] != "o1" {
+		t.Fatalf("unexpected error: %v", e)
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	cases := map[int64]int{
+		errors.ErrorMessage:         http.StatusBadRequest,
+		errors.ErrorMessageNotFount: http.StatusNotFound,
+		errors.ErrorSystemDB:        http.StatusInternalServerError,
+		errors.ErrorSystemRPC:       http.StatusBadGateway,
+		errors.ErrorSystemNet:       http.StatusServiceUnavailable,
+	}
+	for code, status := range cases {
+		if StatusCode(code) != status {
+			t.Fatalf("code 0x%x: expected %d, got %d", code, status, StatusCode(code))
+		}
+	}
+	if ParseProblem(http.StatusForbidden, []byte("forbidden")).GetCode() != errors.ErrorMessage {
+		t.Fatal("non problem body should be mapped by status code")
+	}
+}
+
+func TestParseThirdPartyProblem(t *testing.T) {
+	e := ParseProblem(http.StatusBadRequest, []byte(`{"code":1001,"message":"invalid coupon"}`))
+	if e.GetCode() != errors.ErrorMessage || e.Error() != "invalid coupon" {
+		t.Fatalf("third-party code should not be parsed as an error code, got 0x%x %q", e.GetCode(), e.Error())
+	}
+	e = ParseProblem(http.StatusServiceUnavailable, []byte(`{"code":7,"title":"Service Unavailable","detail":"maintenance"}`))
+	if !errors.IsNetError(e) || e.Error() != "maintenance" || !e.IsRetryable() {
+		t.Fatalf("unexpected error: 0x%x %q", e.GetCode(), e.Error())
+	}
+}
@@ -0,0 +1,210 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultLanguage 没有匹配的语言时使用的语言
+var DefaultLanguage = "zh-CN"
+
+var messageCatalog = struct {
+	sync.RWMutex
+	// language -> key -> template, language 与 key 均为小写
+	messages map[string]map[string]*template.Template
+}{messages: make(map[string]map[string]*template.Template)}
+
+func init() {
+	RegisterMessages("zh-CN", map[string]string{
+		"SYSTEM":          "系统异常",
+		"SYSTEM_INTERNAL": "内部错误",
+		"SYSTEM_DB":       "数据库异常",
+		"SYSTEM_REDIS":    "缓存异常",
+		"SYSTEM_RPC":      "远程调用异常",
+		"SYSTEM_NET":      "网络异常",
+		"SYSTEM_TIMEOUT":  "请求超时",
+		"SYSTEM_CANCELED": "请求已取消",
+
+		"base.error.parse":              "无法解析错误消息",
+		"base.utils.interface_notfound": "获取指定网络接口失败",
+		"base.utils.interface_addr":     "获取指定网络接口地址失败",
+		"base.utils.local_ip":           "获取本地Ip地址失败",
+		"base.utils.no_active_ip":       "没有可用的有效 Ip",
+		"base.utils.empty_addr":         "服务地址不能为空",
+		"base.utils.invalid_addr":       "服务地址不是一个标准的tcp地址",
+	})
+	RegisterMessages("en", map[string]string{
+		"SYSTEM":          "System error",
+		"SYSTEM_INTERNAL": "Internal error",
+		"SYSTEM_DB":       "Database error",
+		"SYSTEM_REDIS":    "Cache error",
+		"SYSTEM_RPC":      "Remote call error",
+		"SYSTEM_NET":      "Network error",
+		"SYSTEM_TIMEOUT":  "Request timed out",
+		"SYSTEM_CANCELED": "Request canceled",
+
+		"base.error.parse":              "Unable to parse error message",
+		"base.utils.interface_notfound": "Failed to get the specified network interface",
+		"base.utils.interface_addr":     "Failed to get the address of the specified network interface",
+		"base.utils.local_ip":           "Failed to get the local IP address",
+		"base.utils.no_active_ip":       "No available IP address",
+		"base.utils.empty_addr":         "Service address must not be empty",
+		"base.utils.invalid_addr":       "Service address is not a valid tcp address",
+	})
+}
+
+// RegisterMessages 注册指定语言的消息模板, key 为消息 key(WithMessageKey)或错误码名称,
+// 模板使用 text/template 语法,数据为错误的元数据,例如 "用户 {{.userId}} 不存在".
+// 按错误码名称配置的消息会覆盖业务错误本身的消息
+func RegisterMessages(lang string, messages map[string]string) error {
+	parsed := make(map[string]*template.Template, len(messages))
+	for key, message := range messages {
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(message)
+		if err != nil {
+			return fmt.Errorf("解析消息模板 %s.%s 失败: %w", lang, key, err)
+		}
+		parsed[strings.ToLower(key)] = tmpl
+	}
+	lang = strings.ToLower(lang)
+	messageCatalog.Lock()
+	defer messageCatalog.Unlock()
+	if messageCatalog.messages[lang] == nil {
+		messageCatalog.messages[lang] = make(map[string]*template.Template, len(parsed))
+	}
+	for key, tmpl := range parsed {
+		messageCatalog.messages[lang][key] = tmpl
+	}
+	return nil
+}
+
+// LoadMessages 从 viper 加载消息目录,key 为空时读取根节点,格式为 语言 -> 消息key -> 模板:
+//
+//	errors_i18n:
+//	  en:
+//	    ORDER_CLOSED: "order {{.orderId}} is closed"
+//	  zh-CN:
+//	    ORDER_CLOSED: "订单 {{.orderId}} 已关闭"
+func LoadMessages(v *viper.Viper, key string) error {
+	settings := v.AllSettings()
+	if key != "" {
+		settings = v.GetStringMap(key)
+	}
+	for lang, value := range settings {
+		messages, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("语言 %s 的消息配置格式错误", lang)
+		}
+		converted := make(map[string]string, len(messages))
+		flattenMessages("", messages, converted)
+		if err := RegisterMessages(lang, converted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenMessages viper 会把带 . 的 key 解析为嵌套结构,这里还原为完整的 key
+func flattenMessages(prefix string, messages map[string]interface{}, out map[string]string) {
+	for key, value := range messages {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenMessages(key, v, out)
+		default:
+			out[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// LoadMessagesFile 从 yaml/json 等 viper 支持的文件加载消息目录
+func LoadMessagesFile(path string) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return err
+	}
+	return LoadMessages(v, "")
+}
+
+// Languages 返回消息目录中已有的语言
+func Languages() []string {
+	messageCatalog.RLock()
+	defer messageCatalog.RUnlock()
+	langs := make([]string, 0, len(messageCatalog.messages))
+	for lang := range messageCatalog.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// PreferredLanguage 从 Accept-Language 格式的字符串中选出消息目录支持的语言,
+// 没有支持的语言时返回 DefaultLanguage
+func PreferredLanguage(acceptLanguage string) string {
+	messageCatalog.RLock()
+	defer messageCatalog.RUnlock()
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		for _, lang := range languageChain(tag) {
+			if _, ok := messageCatalog.messages[lang]; ok {
+				return tag
+			}
+		}
+	}
+	return DefaultLanguage
+}
+
+// languageChain 语言的查找顺序,例如 en-us -> en
+func languageChain(lang string) []string {
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	chain := []string{lang}
+	if i := strings.Index(lang, "-"); i > 0 {
+		chain = append(chain, lang[:i])
+	}
+	return chain
+}
+
+// Localize 返回错误在指定语言下的对外消息,依次使用消息 key、错误码名称查找消息目录,
+// 指定语言没有时使用 DefaultLanguage,都没有时返回 PublicMessage
+func Localize(err Error, lang string) string {
+	if err == nil {
+		return ""
+	}
+	keys := make([]string, 0, 2)
+	if key := MessageKey(err); key != "" {
+		keys = append(keys, strings.ToLower(key))
+	}
+	if name := CodeName(err.GetCode()); name != "" {
+		keys = append(keys, strings.ToLower(name))
+	}
+	langs := append(languageChain(lang), languageChain(DefaultLanguage)...)
+	messageCatalog.RLock()
+	defer messageCatalog.RUnlock()
+	for _, key := range keys {
+		for _, l := range langs {
+			tmpl, ok := messageCatalog.messages[l][key]
+			if !ok {
+				continue
+			}
+			var builder strings.Builder
+			data := GetDetails(err)
+			if data == nil {
+				data = map[string]interface{}{}
+			}
+			if e := tmpl.Execute(&builder, data); e == nil {
+				return builder.String()
+			}
+		}
+	}
+	return PublicMessage(err)
+}
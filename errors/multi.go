@@ -0,0 +1,268 @@
+package errors
+
+import (
+	"encoding/json"
+	errors1 "errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// MultiItem MultiError 中的一个子错误,按序号添加时 Key 为空,按 key 添加时 Index 为 -1
+type MultiItem struct {
+	Index int
+	Key   string
+	Err   Error
+}
+
+func (item MultiItem) label() string {
+	if item.Index < 0 {
+		return "[" + item.Key + "]"
+	}
+	return "[" + strconv.Itoa(item.Index) + "]"
+}
+
+// MultiError 批量操作的错误集合,错误码由子错误决定:
+// 子错误的错误码相同时使用该错误码,包含系统错误时为 ErrorSystem,否则为 ErrorMessage.
+// Add 不是并发安全的
+type MultiError struct {
+	*baseError
+	items []MultiItem
+}
+
+// NewMultiError 创建空的错误集合, message 为整体的消息,例如 "批量导入失败"
+func NewMultiError(message string, opts ...Option) *MultiError {
+	return &MultiError{baseError: newError(ErrorMessage, message, nil, nil, append(append([]Option(nil), opts...), withoutNotify()))}
+}
+
+// Add 添加第 index 项的错误, err 为 nil 时忽略
+func (m *MultiError) Add(index int, err error) *MultiError {
+	return m.add(MultiItem{Index: index}, err)
+}
+
+// AddKey 添加指定 key 的错误, err 为 nil 时忽略
+func (m *MultiError) AddKey(key string, err error) *MultiError {
+	return m.add(MultiItem{Index: -1, Key: key}, err)
+}
+
+func (m *MultiError) add(item MultiItem, err error) *MultiError {
+	if err == nil {
+		return m
+	}
+	item.Err = ConverError(err)
+	m.items = append(m.items, item)
+	m.Code = m.deriveCode()
+	return m
+}
+
+// deriveCode 按子错误计算整体的错误码,系统错误比业务错误严重
+func (m *MultiError) deriveCode() int64 {
+	if len(m.items) == 0 {
+		return ErrorMessage
+	}
+	code, same, system := m.items[0].Err.GetCode(), true, false
+	for _, item := range m.items {
+		c := item.Err.GetCode()
+		if c != code {
+			same = false
+		}
+		if !MatchCode(c, ErrorMessage) {
+			system = true
+		}
+	}
+	switch {
+	case same:
+		return code
+	case system:
+		return ErrorSystem
+	default:
+		return ErrorMessage
+	}
+}
+
+// Items 返回所有子错误
+func (m *MultiError) Items() []MultiItem {
+	return m.items
+}
+
+// Len 子错误的数量
+func (m *MultiError) Len() int {
+	return len(m.items)
+}
+
+// ErrorOrNil 没有子错误时返回 nil,否则返回 m,避免返回非 nil 的空集合.
+// 错误码在添加子错误后才确定,因此在这里通知错误观察者
+func (m *MultiError) ErrorOrNil() Error {
+	if m == nil || len(m.items) == 0 {
+		return nil
+	}
+	notify(EventCreated, m)
+	return m
+}
+
+// Error 返回整体消息和所有子错误的消息
+func (m *MultiError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(m.Message)
+	for i, item := range m.items {
+		if i == 0 {
+			builder.WriteString(": ")
+		} else {
+			builder.WriteString("; ")
+		}
+		builder.WriteString(item.label())
+		builder.WriteByte(' ')
+		builder.WriteString(item.Err.Error())
+	}
+	return builder.String()
+}
+
+// Unwrap 返回所有子错误和原因,供 errors.Is/errors.As 使用
+func (m *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(m.items)+len(m.causes))
+	for _, item := range m.items {
+		errs = append(errs, item.Err)
+	}
+	return append(errs, m.causes...)
+}
+
+// ToError 返回合并了所有子错误的 error
+func (m *MultiError) ToError() error {
+	if len(m.items) == 0 {
+		return errors1.New(m.Message)
+	}
+	errs := make([]error, 0, len(m.items))
+	for _, item := range m.items {
+		errs = append(errs, item.Err)
+	}
+	return errors1.Join(errs...)
+}
+
+func (m *MultiError) WithField(key string, value interface{}) Error {
+	return m.WithFields(map[string]interface{}{key: value})
+}
+
+func (m *MultiError) WithFields(fields map[string]interface{}) Error {
+	return &MultiError{baseError: m.baseError.WithFields(fields).(*baseError), items: m.items}
+}
+
+func (m *MultiError) GetFields(fields ...zap.Field) []zap.Field {
+	notify(EventLogged, m)
+	return append(m.logFields(false), fields...)
+}
+
+func (m *MultiError) GetFieldsWithCause(fields ...zap.Field) []zap.Field {
+	notify(EventLogged, m)
+	return append(m.logFields(true), fields...)
+}
+
+func (m *MultiError) logFields(withCause bool) []zap.Field {
+	return append(m.baseError.logFields(withCause), zap.Array("errors", multiItems{items: m.items, withCause: withCause}))
+}
+
+func (m *MultiError) toWire() *jsonError {
+	data := m.baseError.toWire()
+	for _, item := range m.items {
+		child := causeToWire(item.Err)
+		if item.Index < 0 {
+			child.Key = item.Key
+		} else {
+			index := item.Index
+			child.Index = &index
+		}
+		data.Items = append(data.Items, data.child(child))
+	}
+	return data
+}
+
+func (m *MultiError) MarshalJSON() ([]byte, error) {
+	data := m.toWire()
+	data.Version = WireVersion
+	return json.Marshal(data)
+}
+
+func (m *MultiError) UnmarshalJSON(raw []byte) error {
+	data := &jsonError{}
+	if e := json.Unmarshal(raw, data); e != nil {
+		return e
+	}
+	if e := data.checkVersion(); e != nil {
+		return e
+	}
+	*m = *data.toMultiError()
+	return nil
+}
+
+func (m *MultiError) FormatRPCError() string {
+	data, _ := json.Marshal(m)
+	return string(data)
+}
+
+// Format 实现 fmt.Formatter, %+v 会输出每个子错误的原因链和调用栈
+func (m *MultiError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "%+v", m.baseError)
+		for _, item := range m.items {
+			fmt.Fprintf(s, "\n%s %+v", item.label(), item.Err)
+		}
+		return
+	}
+	switch verb {
+	case 'v', 's':
+		io.WriteString(s, m.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", m.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(errors.MultiError=%s)", verb, m.Error())
+	}
+}
+
+type multiItems struct {
+	items     []MultiItem
+	withCause bool
+}
+
+func (items multiItems) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, item := range items.items {
+		if err := enc.AppendObject(multiItemObject{item: item, withCause: items.withCause}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type multiItemObject struct {
+	item      MultiItem
+	withCause bool
+}
+
+func (obj multiItemObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if obj.item.Index < 0 {
+		enc.AddString("key", obj.item.Key)
+	} else {
+		enc.AddInt("index", obj.item.Index)
+	}
+	if !obj.withCause {
+		enc.AddString("error", obj.item.Err.Error())
+	}
+	// 子错误的字段属于父错误的同一条日志,不再单独通知观察者
+	var fields []zap.Field
+	switch err := obj.item.Err.(type) {
+	case fieldBuilder:
+		fields = err.logFields(obj.withCause)
+	default:
+		if obj.withCause {
+			fields = err.GetFieldsWithCause()
+		} else {
+			fields = err.GetFields()
+		}
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package errors
+
+import "sync/atomic"
+
+// Event 观察到的错误事件
+type Event int
+
+const (
+	// EventCreated 构建错误,不包括 WithField 复制和反序列化得到的错误
+	EventCreated Event = iota
+	// EventLogged 通过 GetFields/GetFieldsWithCause 输出日志字段, MultiError 的子错误随父错误输出,不单独通知
+	EventLogged
+)
+
+func (e Event) String() string {
+	switch e {
+	case EventCreated:
+		return "created"
+	case EventLogged:
+		return "logged"
+	default:
+		return "unknown"
+	}
+}
+
+// Observer 错误观察者,用于统计等用途,会在构建错误和输出日志字段的调用方 goroutine 中同步调用,
+// 必须并发安全且开销小
+type Observer func(event Event, err Error)
+
+var observer atomic.Pointer[Observer]
+
+// SetObserver 设置错误观察者,nil 表示关闭,默认关闭
+func SetObserver(o Observer) {
+	if o == nil {
+		observer.Store(nil)
+		return
+	}
+	observer.Store(&o)
+}
+
+func withoutNotify() Option {
+	return func(opts *options) {
+		opts.silent = true
+	}
+}
+
+func notify(event Event, err Error) {
+	if o := observer.Load(); o != nil {
+		(*o)(event, err)
+	}
+}
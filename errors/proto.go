@@ -0,0 +1,145 @@
+package errors
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ToProto 将错误转换为 protobuf 格式,内容与 FormatRPCError 的 json 一致,不是 Error 时先通过 ConverError 转换
+func ToProto(err error) *ErrorBody {
+	if err == nil {
+		return nil
+	}
+	var data *jsonError
+	e := ConverError(err)
+	if we, ok := e.(wireError); ok {
+		data = we.toWire()
+	} else {
+		data = causeToWire(e)
+		data.Service = serviceName
+	}
+	body := data.toProto()
+	body.Version = WireVersion
+	return body
+}
+
+// FromProto 从 protobuf 格式还原错误,body 为 nil 时返回 nil,
+// 缺少错误码或版本高于 WireVersion 时返回 ErrorSystemRPC
+func FromProto(body *ErrorBody) Error {
+	if body == nil {
+		return nil
+	}
+	if body.GetCode() == 0 {
+		return newParseError(body.String(), errMissingCode)
+	}
+	data := fromProto(body)
+	if e := data.checkVersion(); e != nil {
+		return newParseError(body.String(), e)
+	}
+	return data.decode()
+}
+
+func (data *jsonError) toProto() *ErrorBody {
+	body := &ErrorBody{
+		Code:            data.Code,
+		Message:         data.Message,
+		MessageKey:      data.MessageKey,
+		InternalMessage: data.InternalMessage,
+		Details:         protoDetails(data.Details),
+		Retryable:       data.Retryable,
+		Timeout:         data.Timeout,
+		RetryAfterMs:    data.RetryAfter,
+		Service:         data.Service,
+		Stack:           data.Stack,
+		TraceId:         data.TraceID,
+		SpanId:          data.SpanID,
+		RequestId:       data.RequestID,
+	}
+	for _, cause := range data.Causes {
+		body.Causes = append(body.Causes, cause.toProto())
+	}
+	for _, item := range data.Items {
+		body.Items = append(body.Items, item.toProto())
+	}
+	if data.Index != nil {
+		index := int64(*data.Index)
+		body.Index = &index
+	}
+	body.Key = data.Key
+	for _, v := range data.Violations {
+		body.Violations = append(body.Violations, &ViolationBody{Field: v.Field, Rule: v.Rule, Message: v.Message})
+	}
+	return body
+}
+
+func fromProto(body *ErrorBody) *jsonError {
+	data := &jsonError{
+		Version:         int(body.GetVersion()),
+		Code:            body.GetCode(),
+		Message:         body.GetMessage(),
+		MessageKey:      body.GetMessageKey(),
+		InternalMessage: body.GetInternalMessage(),
+		Retryable:       body.Retryable,
+		Timeout:         body.GetTimeout(),
+		RetryAfter:      body.GetRetryAfterMs(),
+		Service:         body.GetService(),
+		Stack:           body.GetStack(),
+		TraceID:         body.GetTraceId(),
+		SpanID:          body.GetSpanId(),
+		RequestID:       body.GetRequestId(),
+	}
+	if len(body.GetDetails()) > 0 {
+		data.Details = make(map[string]interface{}, len(body.GetDetails()))
+		for key, value := range body.GetDetails() {
+			data.Details[key] = value.AsInterface()
+		}
+	}
+	for _, cause := range body.GetCauses() {
+		if cause != nil {
+			data.Causes = append(data.Causes, fromProto(cause))
+		}
+	}
+	for _, item := range body.GetItems() {
+		if item != nil {
+			data.Items = append(data.Items, fromProto(item))
+		}
+	}
+	if body.Index != nil {
+		index := int(body.GetIndex())
+		data.Index = &index
+	}
+	data.Key = body.GetKey()
+	for _, v := range body.GetViolations() {
+		data.Violations = append(data.Violations, Violation{Field: v.GetField(), Rule: v.GetRule(), Message: v.GetMessage()})
+	}
+	return data
+}
+
+// protoDetails 转换元数据,无法直接表示的值(结构体、time.Time 等)先按 json 转换,与 json 格式保持一致
+func protoDetails(details map[string]interface{}) map[string]*structpb.Value {
+	if len(details) == 0 {
+		return nil
+	}
+	values := make(map[string]*structpb.Value, len(details))
+	for key, value := range details {
+		v, err := structpb.NewValue(value)
+		if err != nil {
+			v = jsonValue(value)
+		}
+		values[key] = v
+	}
+	return values
+}
+
+func jsonValue(value interface{}) *structpb.Value {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return structpb.NewNullValue()
+	}
+	v := &structpb.Value{}
+	if err := v.UnmarshalJSON(raw); err != nil {
+		return structpb.NewNullValue()
+	}
+	return v
+}
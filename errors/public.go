@@ -0,0 +1,76 @@
+package errors
+
+// DefaultPublicMessage 系统错误没有设置对外消息且错误码未注册默认消息时使用的对外消息
+var DefaultPublicMessage = "系统异常"
+
+var debugMode = false
+
+// SetDebugMode 设置调试模式,调试模式下序列化时会同时输出内部诊断消息,不要在生产环境开启
+func SetDebugMode(debug bool) {
+	debugMode = debug
+}
+
+// IsDebugMode 是否处于调试模式
+func IsDebugMode() bool {
+	return debugMode
+}
+
+// WithPublicMessage 设置返回给调用方的消息, Error() 仍然返回内部诊断消息
+func WithPublicMessage(message string) Option {
+	return func(opts *options) {
+		opts.publicMessage = message
+	}
+}
+
+// WithMessageKey 设置对外消息的国际化 key
+func WithMessageKey(key string) Option {
+	return func(opts *options) {
+		opts.messageKey = key
+	}
+}
+
+// PublicMessage 返回错误对调用方可见的消息.
+// 业务错误默认使用错误消息本身,系统错误默认使用错误码注册的默认消息,避免泄露内部细节
+func PublicMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	if e, ok := err.(interface{ PublicMessage() string }); ok {
+		return e.PublicMessage()
+	}
+	if e, ok := err.(Error); ok {
+		return defaultPublicMessage(e.GetCode(), e.Error())
+	}
+	return DefaultPublicMessage
+}
+
+// MessageKey 返回对外消息的国际化 key,没有设置时返回空字符串
+func MessageKey(err error) string {
+	if e, ok := err.(interface{ MessageKey() string }); ok {
+		return e.MessageKey()
+	}
+	return ""
+}
+
+func defaultPublicMessage(code int64, message string) string {
+	if MatchCode(code, ErrorMessage) {
+		return message
+	}
+	if info, ok := LookupCode(code); ok && info.Message != "" {
+		return info.Message
+	}
+	return DefaultPublicMessage
+}
+
+// PublicMessage 返回对调用方可见的消息
+func (err *baseError) PublicMessage() string {
+	if err.publicMessage != "" {
+		return err.publicMessage
+	}
+	return defaultPublicMessage(err.Code, err.Message)
+}
+
+// MessageKey 返回对外消息的国际化 key
+func (err *baseError) MessageKey() string {
+	return err.messageKey
+}
@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/coffeehc/base/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Recover 在 defer 中直接调用,将 panic 转换为 ErrorSystemInternal 赋值给 err 并记录日志,
+// err 为 nil 时只记录日志:
+//
+//	func handle() (err error) {
+//		defer errors.Recover(&err)
+//		...
+//	}
+func Recover(err *error) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	e := panicError(value)
+	logPanic(e)
+	if err != nil {
+		*err = e
+	}
+}
+
+// SafeGo 启动 goroutine 执行 fn, fn 中的 panic 会被转换为错误并记录日志,不会导致进程退出
+func SafeGo(fn func()) {
+	go func() {
+		defer Recover(nil)
+		fn()
+	}()
+}
+
+// SafeCall 执行 fn 并将返回的错误通过 ConverError 转换, fn 中的 panic 会被转换为 ErrorSystemInternal
+func SafeCall(fn func() error) (err Error) {
+	defer func() {
+		if value := recover(); value != nil {
+			e := panicError(value)
+			logPanic(e)
+			err = e
+		}
+	}()
+	return ConverError(fn())
+}
+
+// panicError 将 panic 的值转换为 ErrorSystemInternal,总是记录从 panic 发生处开始的调用栈,
+// 需要在 panic 的 defer 过程中调用
+func panicError(value interface{}) *baseError {
+	var causes []error
+	message := fmt.Sprintf("panic: %v", value)
+	cause, ok := value.(error)
+	if ok {
+		causes = []error{cause}
+	} else {
+		cause = fmt.Errorf("%v", value)
+	}
+	err := newError(ErrorSystemInternal, message, cause, causes, []Option{WithoutStack()})
+	err.stack = panicStack(callers(0))
+	return err
+}
+
+// panicStack 去掉 recover 与 runtime.gopanic 之间的帧,使调用栈从 panic 发生处开始
+func panicStack(pcs []uintptr) []uintptr {
+	for i, pc := range pcs {
+		if fn := runtime.FuncForPC(pc - 1); fn == nil || fn.Name() != "runtime.gopanic" {
+			continue
+		}
+		pcs = pcs[i+1:]
+		// 空指针、除零等运行时错误还会经过 runtime.panicmem、runtime.sigpanic 等帧
+		for len(pcs) > 0 {
+			fn := runtime.FuncForPC(pcs[0] - 1)
+			if fn == nil || !strings.HasPrefix(fn.Name(), "runtime.") {
+				break
+			}
+			pcs = pcs[1:]
+		}
+		return pcs
+	}
+	return pcs
+}
+
+func panicCaller(pcs []uintptr) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames(pcs[:1]).Next()
+	return fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line)
+}
+
+func logPanic(err *baseError) {
+	log.SendLog(zapcore.ErrorLevel, "捕获到 panic", err.GetFieldsWithCause(zap.String("panicCaller", panicCaller(err.stack)))...)
+}
@@ -0,0 +1,142 @@
+package errors
+
+import (
+	"encoding/json"
+	errors1 "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CodeInfo 错误码的描述信息
+type CodeInfo struct {
+	Code        int64  `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Message 使用 BuildErrorByCode 构建错误时的默认消息
+	Message string `json:"message,omitempty"`
+}
+
+type codeRegistry struct {
+	mutex  sync.RWMutex
+	codes  map[int64]CodeInfo
+	byName map[string]int64
+}
+
+var registry = &codeRegistry{
+	codes:  make(map[int64]CodeInfo),
+	byName: make(map[string]int64),
+}
+
+func init() {
+	RegisterCodes(
+		CodeInfo{Code: ErrorSystem, Name: "SYSTEM", Description: "系统级别的错误,包括IO异常,空指针等", Message: "系统异常"},
+		CodeInfo{Code: ErrorSystemInternal, Name: "SYSTEM_INTERNAL", Description: "内部错误", Message: "内部错误"},
+		CodeInfo{Code: ErrorSystemDB, Name: "SYSTEM_DB", Description: "数据库错误", Message: "数据库异常"},
+		CodeInfo{Code: ErrorSystemRedis, Name: "SYSTEM_REDIS", Description: "Redis 错误", Message: "缓存异常"},
+		CodeInfo{Code: ErrorSystemRPC, Name: "SYSTEM_RPC", Description: "RPC错误,包含编解码", Message: "远程调用异常"},
+		CodeInfo{Code: ErrorSystemNet, Name: "SYSTEM_NET", Description: "网络错误", Message: "网络异常"},
+		CodeInfo{Code: ErrorSystemTimeout, Name: "SYSTEM_TIMEOUT", Description: "超时", Message: "请求超时"},
+		CodeInfo{Code: ErrorSystemCanceled, Name: "SYSTEM_CANCELED", Description: "调用被取消", Message: "请求已取消"},
+		CodeInfo{Code: ErrorMessage, Name: "MESSAGE", Description: "业务相关的异常", Message: "业务异常"},
+		CodeInfo{Code: ErrorMessageNotFount, Name: "MESSAGE_NOT_FOUND", Description: "未找到", Message: "未找到"},
+		CodeInfo{Code: ErrorMessageValidation, Name: "MESSAGE_VALIDATION", Description: "参数校验失败", Message: "参数校验失败"},
+	)
+}
+
+// RegisterCode 注册错误码,错误码或名称重复时 panic,应在 init 中调用
+func RegisterCode(info CodeInfo) {
+	if info.Name == "" {
+		panic(fmt.Sprintf("错误码 0x%x 没有名称", info.Code))
+	}
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if exist, ok := registry.codes[info.Code]; ok {
+		panic(fmt.Sprintf("错误码 0x%x 重复注册: %s 与 %s", info.Code, exist.Name, info.Name))
+	}
+	if code, ok := registry.byName[info.Name]; ok {
+		panic(fmt.Sprintf("错误码名称 %s 重复注册: 0x%x 与 0x%x", info.Name, code, info.Code))
+	}
+	registry.codes[info.Code] = info
+	registry.byName[info.Name] = info.Code
+}
+
+// RegisterCodes 批量注册错误码
+func RegisterCodes(infos ...CodeInfo) {
+	for _, info := range infos {
+		RegisterCode(info)
+	}
+}
+
+// LookupCode 查找错误码的描述信息
+func LookupCode(code int64) (CodeInfo, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	info, ok := registry.codes[code]
+	return info, ok
+}
+
+// LookupCodeByName 根据名称查找错误码
+func LookupCodeByName(name string) (CodeInfo, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	code, ok := registry.byName[name]
+	if !ok {
+		return CodeInfo{}, false
+	}
+	return registry.codes[code], true
+}
+
+// CodeName 返回错误码的名称,未注册时返回空字符串
+func CodeName(code int64) string {
+	info, _ := LookupCode(code)
+	return info.Name
+}
+
+// Codes 返回所有已注册的错误码,按错误码排序
+func Codes() []CodeInfo {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	infos := make([]CodeInfo, 0, len(registry.codes))
+	for _, info := range registry.codes {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Code < infos[j].Code
+	})
+	return infos
+}
+
+// CatalogJSON 以 json 格式导出错误码目录
+func CatalogJSON() ([]byte, error) {
+	return json.MarshalIndent(Codes(), "", "  ")
+}
+
+// CatalogMarkdown 以 Markdown 表格导出错误码目录
+func CatalogMarkdown() string {
+	var builder strings.Builder
+	builder.WriteString("| 错误码 | 名称 | 描述 | 默认消息 |\n")
+	builder.WriteString("|--------|------|------|----------|\n")
+	for _, info := range Codes() {
+		fmt.Fprintf(&builder, "| `0x%x` | `%s` | %s | %s |\n", info.Code, info.Name,
+			escapeMarkdown(info.Description), escapeMarkdown(info.Message))
+	}
+	return builder.String()
+}
+
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+// BuildErrorByCode 使用注册的默认消息构建错误,未注册时使用错误码作为消息
+func BuildErrorByCode(errorCode int64, opts ...Option) Error {
+	message := fmt.Sprintf("0x%x", errorCode)
+	if info, ok := LookupCode(errorCode); ok {
+		message = info.Message
+		if message == "" {
+			message = info.Name
+		}
+	}
+	return newError(errorCode, message, errors1.New(message), nil, opts)
+}
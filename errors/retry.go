@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"time"
+)
+
+// WithRetryable 设置错误是否可以重试,不设置时从原因链中继承
+func WithRetryable(retryable bool) Option {
+	return func(opts *options) {
+		opts.retryable = &retryable
+	}
+}
+
+// WithTimeout 标记错误为超时错误,超时错误默认可以重试
+func WithTimeout() Option {
+	return func(opts *options) {
+		opts.timeout = true
+	}
+}
+
+// WithRetryAfter 设置建议的重试间隔,同时标记错误可以重试
+func WithRetryAfter(d time.Duration) Option {
+	return func(opts *options) {
+		opts.retryAfter = d
+	}
+}
+
+// applyRetry 根据选项和原因链设置重试信息,选项优先
+func (err *baseError) applyRetry(o *options) {
+	for _, cause := range err.causes {
+		if IsTimeout(cause) {
+			err.timeout = true
+		}
+		if IsRetryable(cause) {
+			err.retryable = true
+		}
+		if IsRetryableSet(cause) {
+			err.retrySet = true
+		}
+		if d := RetryAfter(cause); d > err.retryAfter {
+			err.retryAfter = d
+		}
+	}
+	if o.timeout {
+		err.timeout = true
+		err.retryable = true
+	}
+	if o.retryAfter > 0 {
+		err.retryAfter = o.retryAfter
+		err.retryable = true
+	}
+	if o.retryable != nil {
+		err.retryable = *o.retryable
+		err.retrySet = true
+	}
+}
+
+// IsRetryable 错误是否可以重试
+func (err *baseError) IsRetryable() bool {
+	return err.retryable
+}
+
+// IsRetryableSet 是否显式设置了可否重试,包括从原因链继承的设置
+func (err *baseError) IsRetryableSet() bool {
+	return err.retrySet
+}
+
+// IsTimeout 错误是否为超时
+func (err *baseError) IsTimeout() bool {
+	return err.timeout
+}
+
+// RetryAfter 建议的重试间隔,0 表示没有建议
+func (err *baseError) RetryAfter() time.Duration {
+	return err.retryAfter
+}
+
+// IsRetryable 判断错误是否可以重试,会沿错误链查找,
+// 支持实现了 IsRetryable() 或 Temporary()/Timeout() 的错误,例如 net.Error
+func IsRetryable(err error) bool {
+	var retryable interface{ IsRetryable() bool }
+	if As(err, &retryable) {
+		return retryable.IsRetryable()
+	}
+	var temporary interface{ Temporary() bool }
+	if As(err, &temporary) && temporary.Temporary() {
+		return true
+	}
+	return IsTimeout(err)
+}
+
+// IsRetryableSet 判断错误是否通过 WithRetryable 显式设置了可否重试,
+// 用于区分显式标记为不可重试的错误和没有设置的错误
+func IsRetryableSet(err error) bool {
+	var set interface{ IsRetryableSet() bool }
+	return As(err, &set) && set.IsRetryableSet()
+}
+
+// IsTimeout 判断错误是否为超时,会沿错误链查找实现了 IsTimeout() 或 Timeout() 的错误
+func IsTimeout(err error) bool {
+	var timeout interface{ IsTimeout() bool }
+	if As(err, &timeout) {
+		return timeout.IsTimeout()
+	}
+	var netTimeout interface{ Timeout() bool }
+	return As(err, &netTimeout) && netTimeout.Timeout()
+}
+
+// RetryAfter 返回错误链中建议的重试间隔,没有时返回 0
+func RetryAfter(err error) time.Duration {
+	var retryAfter interface{ RetryAfter() time.Duration }
+	if As(err, &retryAfter) {
+		return retryAfter.RetryAfter()
+	}
+	return 0
+}
@@ -0,0 +1,30 @@
+package errors
+
+// 错误类别的哨兵错误,用于 errors.Is 按类别匹配
+var (
+	// ErrorClassSystem 匹配所有系统级别的错误
+	ErrorClassSystem = NewSentinel(ErrorSystem, "系统异常")
+	// ErrorClassMessage 匹配所有业务级别的错误
+	ErrorClassMessage = NewSentinel(ErrorMessage, "业务异常")
+)
+
+// 内置错误码的哨兵错误,用于 errors.Is 匹配,也可以直接返回
+var (
+	ErrInternal   = NewSentinel(ErrorSystemInternal, "内部错误")
+	ErrDB         = NewSentinel(ErrorSystemDB, "数据库异常")
+	ErrRedis      = NewSentinel(ErrorSystemRedis, "缓存异常")
+	ErrRPC        = NewSentinel(ErrorSystemRPC, "远程调用异常")
+	ErrNet        = NewSentinel(ErrorSystemNet, "网络异常")
+	ErrTimeout    = NewSentinel(ErrorSystemTimeout, "请求超时")
+	ErrCanceled   = NewSentinel(ErrorSystemCanceled, "请求已取消")
+	ErrNotFound   = NewSentinel(ErrorMessageNotFount, "未找到")
+	ErrValidation = NewSentinel(ErrorMessageValidation, "参数校验失败")
+)
+
+// NewSentinel 创建哨兵错误, errors.Is(err, sentinel) 在 err 的错误码属于 code 范围时返回 true,
+// 例如模块的 Scope 可以匹配模块下的所有错误. 哨兵错误不记录调用栈,也不通知错误观察者
+func NewSentinel(code int64, message string) Error {
+	err := newError(code, message, nil, nil, []Option{WithoutStack(), withoutNotify()})
+	err.sentinel = true
+	return err
+}
@@ -2,14 +2,44 @@ package errors
 
 import errors1 "errors"
 
-func BuildError(errorCode int64, message string) Error {
-	return &baseError{
+// newError 构建 baseError,根据选项和全局策略决定是否记录调用栈
+func newError(errorCode int64, message string, e error, causes []error, opts []Option) *baseError {
+	err := &baseError{
 		Code:    errorCode,
 		Message: message,
-		e:       errors1.New(message),
+		e:       e,
+		causes:  causes,
+	}
+	o := newOptions(opts)
+	if len(o.causes) > 0 {
+		err.causes = append(causes[:len(causes):len(causes)], o.causes...)
+	}
+	err.publicMessage = o.publicMessage
+	err.messageKey = o.messageKey
+	err.applyRetry(o)
+	err.applyTrace(o)
+	if o.captureStack(errorCode) {
+		err.stack = callers(2 + o.callerSkip)
+	}
+	if !o.silent {
+		notify(EventCreated, err)
+	}
+	return err
+}
+
+// WithCause 追加原因,错误的消息不变,原因可以通过 Unwrap 访问
+func WithCause(cause error) Option {
+	return func(opts *options) {
+		if cause != nil {
+			opts.causes = append(opts.causes, cause)
+		}
 	}
 }
 
+func BuildError(errorCode int64, message string, opts ...Option) Error {
+	return newError(errorCode, message, errors1.New(message), nil, opts)
+}
+
 func SystemError(message string) Error {
 	return BuildError(ErrorSystem, message)
 }
@@ -22,12 +52,24 @@ func NotFountError(message string) Error {
 	return BuildError(ErrorMessageNotFount, message)
 }
 
-func WrappedError(errorCode int64, err error) Error {
-	return &baseError{
-		Code:    errorCode,
-		Message: err.Error(),
-		e:       err,
-	}
+func SystemDBError(message string) Error {
+	return BuildError(ErrorSystemDB, message)
+}
+
+func SystemRedisError(message string) Error {
+	return BuildError(ErrorSystemRedis, message)
+}
+
+func SystemRPCError(message string) Error {
+	return BuildError(ErrorSystemRPC, message)
+}
+
+func SystemNetError(message string) Error {
+	return BuildError(ErrorSystemNet, message)
+}
+
+func WrappedError(errorCode int64, err error, opts ...Option) Error {
+	return newError(errorCode, err.Error(), err, []error{err}, opts)
 }
 
 func WrappedSystemError(err error) Error {
@@ -43,3 +85,22 @@ func WrappedMessageError(err error) Error {
 	}
 	return WrappedError(ErrorMessage, err)
 }
+
+// JoinError 将多个错误合并为一个 Error,类似标准库的 errors.Join,
+// nil 会被忽略,全部为 nil 时返回 nil
+func JoinError(errorCode int64, errs ...error) Error {
+	causes := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			causes = append(causes, err)
+		}
+	}
+	if len(causes) == 0 {
+		return nil
+	}
+	if len(causes) == 1 {
+		return WrappedError(errorCode, causes[0])
+	}
+	joined := errors1.Join(causes...)
+	return newError(errorCode, joined.Error(), joined, causes, nil)
+}
@@ -0,0 +1,168 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StackPolicy 根据错误码决定构建错误时是否记录调用栈
+type StackPolicy func(code int64) bool
+
+// StackAlways 所有错误都记录调用栈
+func StackAlways(code int64) bool {
+	return true
+}
+
+// StackForSystemError 只为系统级别的错误记录调用栈
+func StackForSystemError(code int64) bool {
+	return EqualError(code, ErrorSystem)
+}
+
+var stackPolicy StackPolicy
+
+// SetStackPolicy 设置全局的调用栈记录策略,nil 表示不记录,应在初始化阶段调用
+func SetStackPolicy(policy StackPolicy) {
+	stackPolicy = policy
+}
+
+// Option 构建错误时的可选项
+type Option func(opts *options)
+
+type options struct {
+	stack      *bool
+	callerSkip int
+	// silent 不通知观察者,用于 MultiError 等错误码在构建后才确定的错误
+	silent        bool
+	publicMessage string
+	messageKey    string
+	retryable     *bool
+	timeout       bool
+	retryAfter    time.Duration
+	trace         TraceInfo
+	causes        []error
+}
+
+// WithStack 忽略全局策略,强制记录调用栈
+func WithStack() Option {
+	return func(opts *options) {
+		capture := true
+		opts.stack = &capture
+	}
+}
+
+// WithoutStack 忽略全局策略,不记录调用栈
+func WithoutStack() Option {
+	return func(opts *options) {
+		capture := false
+		opts.stack = &capture
+	}
+}
+
+// WithCallerSkip 记录调用栈时额外跳过的调用层数,用于封装了 BuildError 等函数的构建函数
+func WithCallerSkip(skip int) Option {
+	return func(opts *options) {
+		opts.callerSkip += skip
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+func (o *options) captureStack(code int64) bool {
+	if o.stack != nil {
+		return *o.stack
+	}
+	return stackPolicy != nil && stackPolicy(code)
+}
+
+const maxStackDepth = 32
+
+var packagePath = reflect.TypeOf(baseError{}).PkgPath()
+
+// callers 记录调用栈,skip 为需要跳过的调用层数(不包含 callers 本身)
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pcs)
+	return pcs[:n]
+}
+
+// formatStack 以 zap stacktrace 的格式输出调用栈,跳过 errors 包内部的帧
+func formatStack(pcs []uintptr) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	internal := true
+	for {
+		frame, more := frames.Next()
+		if internal && isInternalFrame(frame) {
+			if !more {
+				break
+			}
+			continue
+		}
+		internal = false
+		if builder.Len() > 0 {
+			builder.WriteByte('\n')
+		}
+		builder.WriteString(frame.Function)
+		builder.WriteString("\n\t")
+		builder.WriteString(frame.File)
+		builder.WriteByte(':')
+		builder.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+	}
+	return builder.String()
+}
+
+func isInternalFrame(frame runtime.Frame) bool {
+	return strings.HasPrefix(frame.Function, packagePath+".") && !strings.HasSuffix(frame.File, "_test.go")
+}
+
+// StackTrace 返回构建错误时记录的调用栈,反序列化得到的错误返回来源服务的调用栈,未记录时返回空字符串
+func (err *baseError) StackTrace() string {
+	if len(err.stack) == 0 {
+		return err.remoteStack
+	}
+	return formatStack(err.stack)
+}
+
+// Format 实现 fmt.Formatter, %+v 会输出原因链和调用栈
+func (err *baseError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, err.Message)
+			if stack := err.StackTrace(); stack != "" {
+				io.WriteString(s, "\n")
+				io.WriteString(s, stack)
+			}
+			for _, cause := range err.causes {
+				fmt.Fprintf(s, "\ncaused by: %+v", cause)
+			}
+			return
+		}
+		io.WriteString(s, err.Message)
+	case 's':
+		io.WriteString(s, err.Message)
+	case 'q':
+		fmt.Fprintf(s, "%q", err.Message)
+	default:
+		fmt.Fprintf(s, "%%!%c(errors.Error=%s)", verb, err.Message)
+	}
+}
@@ -1,10 +1,7 @@
 package errors
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
-	// "strings"
 
 	"go.uber.org/zap"
 )
@@ -23,16 +20,14 @@ func NamedScope(name string) zap.Field {
 	return zap.String("scope", name)
 }
 
-func ParseError(jsonStr string) Error {
-	err := &baseError{}
-	e := json.Unmarshal([]byte(jsonStr), err)
-	if e != nil {
-		err.Code = ErrorSystemRPC
-		err.Message = fmt.Sprintf("无法解析错误消息[%s],%#v", jsonStr, e)
-	}
-	return err
+// ParseError 解析 FormatRPCError 输出的 json,总是返回可用的 Error,无法解析时返回 ErrorSystemRPC,
+// causes 作为本地的原因追加到结果中,例如保留携带该 json 的原始错误
+func ParseError(jsonStr string, causes ...error) Error {
+	return parseError([]byte(jsonStr), causes)
 }
 
+// ConverUnknowError 转换 recover 得到的值,不是 error 时转换为 ErrorSystemInternal 并保留原始值,
+// 在 defer 中调用时会记录 panic 发生处的调用栈
 func ConverUnknowError(err interface{}) Error {
 	if err == nil {
 		return nil
@@ -40,9 +35,11 @@ func ConverUnknowError(err interface{}) Error {
 	if e, ok := err.(error); ok {
 		return ConverError(e)
 	}
-	return SystemError("未知异常")
+	return panicError(err)
 }
 
+// ConverError 将 error 转换为 Error,已经是 Error 时直接返回,
+// 否则依次尝试注册的转换器(见 RegisterConverter),都无法识别时包装为 ErrorSystem
 func ConverError(err error) Error {
 	if err == nil {
 		return nil
@@ -50,12 +47,51 @@ func ConverError(err error) Error {
 	if IsBaseError(err) {
 		return err.(Error)
 	}
-	// if strings.HasPrefix(err.Error(), "context ") || strings.HasPrefix(err.Error(), "rpc error:") {
-	// 	return SystemError(err.Error())
-	// }
+	if e := convert(err); e != nil {
+		return e
+	}
 	return WrappedSystemError(err)
 }
 
+// GetDetails 返回错误通过 WithField/WithFields 附加的元数据,没有时返回 nil
+func GetDetails(err error) map[string]interface{} {
+	if e, ok := err.(interface{ GetDetails() map[string]interface{} }); ok {
+		return e.GetDetails()
+	}
+	return nil
+}
+
 func As(err error, target interface{}) bool {
 	return errors.As(err, target)
 }
+
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// Cause 返回 err 的直接原因,包含多个原因时返回第一个,没有原因时返回 nil
+func Cause(err error) error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, cause := range e.Unwrap() {
+			if cause != nil {
+				return cause
+			}
+		}
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	}
+	return nil
+}
+
+// RootCause 沿 Cause 向下查找最底层的错误,err 没有原因时返回 err 本身
+func RootCause(err error) error {
+	for err != nil {
+		cause := Cause(err)
+		if cause == nil {
+			return err
+		}
+		err = cause
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Violation 一个字段的校验失败信息
+type Violation struct {
+	// Field 字段路径,例如 "items[0].name"
+	Field string `json:"field"`
+	// Rule 未通过的校验规则,例如 "required"、"max"
+	Rule string `json:"rule,omitempty"`
+	// Message 返回给调用方的消息
+	Message string `json:"msg"`
+}
+
+// MarshalLogObject 实现 zapcore.ObjectMarshaler
+func (v Violation) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("field", v.Field)
+	if v.Rule != "" {
+		enc.AddString("rule", v.Rule)
+	}
+	enc.AddString("msg", v.Message)
+	return nil
+}
+
+type violations []Violation
+
+func (list violations) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range list {
+		if err := enc.AppendObject(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidationError 参数校验错误,错误码为 ErrorMessageValidation,包含每个字段的校验失败信息,
+// 序列化时以 violations 数组输出,方便前端定位字段. Add 不是并发安全的
+type ValidationError struct {
+	*baseError
+	violations []Violation
+}
+
+// NewValidationError 创建空的校验错误, message 为空时使用 "参数校验失败"
+func NewValidationError(message string, opts ...Option) *ValidationError {
+	if message == "" {
+		message = "参数校验失败"
+	}
+	return &ValidationError{baseError: newError(ErrorMessageValidation, message, nil, nil, append(append([]Option(nil), opts...), withoutNotify()))}
+}
+
+// Add 添加字段的校验失败信息
+func (v *ValidationError) Add(field, rule, message string) *ValidationError {
+	v.violations = append(v.violations, Violation{Field: field, Rule: rule, Message: message})
+	return v
+}
+
+// Violations 返回所有字段的校验失败信息
+func (v *ValidationError) Violations() []Violation {
+	return v.violations
+}
+
+// ErrorOrNil 没有校验失败信息时返回 nil,否则返回 v,同时通知错误观察者
+func (v *ValidationError) ErrorOrNil() Error {
+	if v == nil || len(v.violations) == 0 {
+		return nil
+	}
+	notify(EventCreated, v)
+	return v
+}
+
+// Error 返回整体消息和所有字段的校验失败信息
+func (v *ValidationError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(v.Message)
+	for i, violation := range v.violations {
+		if i == 0 {
+			builder.WriteString(": ")
+		} else {
+			builder.WriteString("; ")
+		}
+		builder.WriteString(violation.Field)
+		builder.WriteByte(' ')
+		builder.WriteString(violation.Message)
+	}
+	return builder.String()
+}
+
+func (v *ValidationError) WithField(key string, value interface{}) Error {
+	return v.WithFields(map[string]interface{}{key: value})
+}
+
+func (v *ValidationError) WithFields(fields map[string]interface{}) Error {
+	return &ValidationError{baseError: v.baseError.WithFields(fields).(*baseError), violations: v.violations}
+}
+
+func (v *ValidationError) GetFields(fields ...zap.Field) []zap.Field {
+	notify(EventLogged, v)
+	return append(v.logFields(false), fields...)
+}
+
+func (v *ValidationError) GetFieldsWithCause(fields ...zap.Field) []zap.Field {
+	notify(EventLogged, v)
+	return append(v.logFields(true), fields...)
+}
+
+func (v *ValidationError) logFields(withCause bool) []zap.Field {
+	return append(v.baseError.logFields(withCause), zap.Array("violations", violations(v.violations)))
+}
+
+func (v *ValidationError) toWire() *jsonError {
+	data := v.baseError.toWire()
+	data.Violations = v.violations
+	return data
+}
+
+func (v *ValidationError) MarshalJSON() ([]byte, error) {
+	data := v.toWire()
+	data.Version = WireVersion
+	return json.Marshal(data)
+}
+
+func (v *ValidationError) UnmarshalJSON(raw []byte) error {
+	data := &jsonError{}
+	if e := json.Unmarshal(raw, data); e != nil {
+		return e
+	}
+	if e := data.checkVersion(); e != nil {
+		return e
+	}
+	*v = *data.toValidationError()
+	return nil
+}
+
+func (v *ValidationError) FormatRPCError() string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// Format 实现 fmt.Formatter
+func (v *ValidationError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's':
+		io.WriteString(s, v.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", v.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(errors.ValidationError=%s)", verb, v.Error())
+	}
+}
@@ -0,0 +1,282 @@
+package errors
+
+import (
+	"encoding/json"
+	errors1 "errors"
+	"fmt"
+	"time"
+)
+
+// WireVersion 当前 json 格式的版本,没有版本号的数据按只包含 code、msg 的旧格式解析,
+// 版本高于 WireVersion 的数据无法解析
+const WireVersion = 1
+
+var serviceName string
+
+// SetServiceName 设置当前服务的名称,序列化时作为错误的来源服务,应在初始化阶段调用
+func SetServiceName(name string) {
+	serviceName = name
+}
+
+// ServiceName 返回 SetServiceName 设置的服务名称
+func ServiceName() string {
+	return serviceName
+}
+
+// jsonError baseError 的 json 格式, msg 只包含对外消息,内部消息和调用栈只在调试模式下输出,
+// causes 为原因链,不是 Error 的原因 code 为 0, items 为 MultiError 的子错误,
+// violations 为 ValidationError 的字段校验失败信息
+type jsonError struct {
+	Version         int                    `json:"v,omitempty"`
+	Code            int64                  `json:"code"`
+	Message         string                 `json:"msg"`
+	MessageKey      string                 `json:"msg_key,omitempty"`
+	InternalMessage string                 `json:"internal_msg,omitempty"`
+	Details         map[string]interface{} `json:"details,omitempty"`
+	Retryable       *bool                  `json:"retryable,omitempty"`
+	Timeout         bool                   `json:"timeout,omitempty"`
+	RetryAfter      int64                  `json:"retry_after_ms,omitempty"`
+	Service         string                 `json:"service,omitempty"`
+	Causes          []*jsonError           `json:"causes,omitempty"`
+	Stack           string                 `json:"stack,omitempty"`
+	Items           []*jsonError           `json:"items,omitempty"`
+	Index           *int                   `json:"index,omitempty"`
+	Key             string                 `json:"key,omitempty"`
+	Violations      []Violation            `json:"violations,omitempty"`
+	TraceID         string                 `json:"trace_id,omitempty"`
+	SpanID          string                 `json:"span_id,omitempty"`
+	RequestID       string                 `json:"request_id,omitempty"`
+}
+
+func (data *jsonError) traceInfo() TraceInfo {
+	return TraceInfo{TraceID: data.TraceID, SpanID: data.SpanID, RequestID: data.RequestID}
+}
+
+func (data *jsonError) setTraceInfo(info TraceInfo) {
+	data.TraceID, data.SpanID, data.RequestID = info.TraceID, info.SpanID, info.RequestID
+}
+
+// child 添加原因或子错误时,与外层相同的链路信息不重复输出
+func (data *jsonError) child(child *jsonError) *jsonError {
+	if child.traceInfo() == data.traceInfo() {
+		child.setTraceInfo(TraceInfo{})
+	}
+	return child
+}
+
+// inherit 还原原因或子错误时,没有链路信息的从外层继承
+func (data *jsonError) inherit(child *jsonError) {
+	if child.Service == "" {
+		child.Service = data.Service
+	}
+	if child.traceInfo().IsZero() {
+		child.setTraceInfo(data.traceInfo())
+	}
+}
+
+// wireError 可以转换为序列化格式的错误
+type wireError interface {
+	toWire() *jsonError
+}
+
+// Service 返回错误的来源服务,本地构建的错误返回当前服务名称
+func (err *baseError) Service() string {
+	if err.service != "" {
+		return err.service
+	}
+	return serviceName
+}
+
+func (err *baseError) MarshalJSON() ([]byte, error) {
+	data := err.toWire()
+	data.Version = WireVersion
+	return json.Marshal(data)
+}
+
+func (err *baseError) UnmarshalJSON(raw []byte) error {
+	data := &jsonError{}
+	if e := json.Unmarshal(raw, data); e != nil {
+		return e
+	}
+	if e := data.checkVersion(); e != nil {
+		return e
+	}
+	if data.Version == 0 {
+		*err = *data.toLegacyError()
+		return nil
+	}
+	*err = *data.toError()
+	return nil
+}
+
+// checkVersion 版本高于 WireVersion 时返回错误
+func (data *jsonError) checkVersion() error {
+	if data.Version > WireVersion {
+		return fmt.Errorf("不支持的版本 %d,当前版本为 %d", data.Version, WireVersion)
+	}
+	return nil
+}
+
+// toLegacyError 没有版本号的旧格式只包含 code、msg, msg 为内部消息,对外消息按错误码的默认策略生成
+func (data *jsonError) toLegacyError() *baseError {
+	return &baseError{Code: data.Code, Message: data.Message, e: errors1.New(data.Message)}
+}
+
+func (err *baseError) toWire() *jsonError {
+	data := &jsonError{
+		Code:       err.Code,
+		Message:    err.PublicMessage(),
+		MessageKey: err.messageKey,
+		Details:    err.Details,
+		Timeout:    err.timeout,
+		RetryAfter: err.retryAfter.Milliseconds(),
+		Service:    err.Service(),
+	}
+	if err.retryable || err.retrySet {
+		retryable := err.retryable
+		data.Retryable = &retryable
+	}
+	data.setTraceInfo(err.trace)
+	if debugMode {
+		if err.Message != data.Message {
+			data.InternalMessage = err.Message
+		}
+		data.Stack = err.StackTrace()
+	}
+	for _, cause := range err.causes {
+		data.Causes = append(data.Causes, data.child(causeToWire(cause)))
+	}
+	return data
+}
+
+func causeToWire(cause error) *jsonError {
+	if e, ok := cause.(wireError); ok {
+		data := e.toWire()
+		if data.Service == serviceName {
+			// 与外层相同的来源服务不重复输出
+			data.Service = ""
+		}
+		return data
+	}
+	data := &jsonError{Message: PublicMessage(cause), MessageKey: MessageKey(cause)}
+	if e, ok := cause.(Error); ok {
+		data.Code = e.GetCode()
+	}
+	if debugMode && cause.Error() != data.Message {
+		data.InternalMessage = cause.Error()
+	}
+	return data
+}
+
+func (data *jsonError) toError() *baseError {
+	err := &baseError{
+		Code:          data.Code,
+		Message:       data.Message,
+		Details:       data.Details,
+		publicMessage: data.Message,
+		messageKey:    data.MessageKey,
+		retryable:     data.Retryable != nil && *data.Retryable,
+		retrySet:      data.Retryable != nil,
+		timeout:       data.Timeout,
+		retryAfter:    time.Duration(data.RetryAfter) * time.Millisecond,
+		service:       data.Service,
+		remoteStack:   data.Stack,
+		trace:         data.traceInfo(),
+	}
+	if data.InternalMessage != "" {
+		err.Message = data.InternalMessage
+	}
+	err.e = errors1.New(err.Message)
+	for _, cause := range data.Causes {
+		if cause == nil {
+			continue
+		}
+		data.inherit(cause)
+		err.causes = append(err.causes, cause.toCause())
+	}
+	return err
+}
+
+// decode 还原为 Error,包含 items 时还原为 MultiError,包含 violations 时还原为 ValidationError
+func (data *jsonError) decode() Error {
+	switch {
+	case len(data.Items) > 0:
+		return data.toMultiError()
+	case len(data.Violations) > 0:
+		return data.toValidationError()
+	default:
+		return data.toError()
+	}
+}
+
+func (data *jsonError) toValidationError() *ValidationError {
+	return &ValidationError{baseError: data.toError(), violations: data.Violations}
+}
+
+func (data *jsonError) toMultiError() *MultiError {
+	m := &MultiError{baseError: data.toError()}
+	for _, item := range data.Items {
+		if item == nil || item.Code == 0 {
+			continue
+		}
+		data.inherit(item)
+		multiItem := MultiItem{Index: -1, Key: item.Key, Err: item.decode()}
+		if item.Index != nil {
+			multiItem.Index = *item.Index
+		}
+		m.items = append(m.items, multiItem)
+	}
+	return m
+}
+
+// toCause code 为 0 的原因还原为普通 error
+func (data *jsonError) toCause() error {
+	if data.Code == 0 {
+		if data.InternalMessage != "" {
+			return errors1.New(data.InternalMessage)
+		}
+		return errors1.New(data.Message)
+	}
+	return data.decode()
+}
+
+// parseError ParseError 与 ParseErrorFromJSON 共用的解析逻辑,
+// 无法解析时返回 ErrorSystemRPC,保证总是返回可用的 Error
+func parseError(raw []byte, causes []error) Error {
+	data := &jsonError{}
+	if e := json.Unmarshal(raw, data); e != nil {
+		return newParseError(string(raw), e, causes...)
+	}
+	if data.Code == 0 {
+		return newParseError(string(raw), errMissingCode, causes...)
+	}
+	if e := data.checkVersion(); e != nil {
+		return newParseError(string(raw), e, causes...)
+	}
+	if data.Version == 0 {
+		return appendCauses(data.toLegacyError(), causes)
+	}
+	return appendCauses(data.decode(), causes)
+}
+
+// appendCauses 为解析得到的错误追加本地的原因
+func appendCauses(err Error, causes []error) Error {
+	if len(causes) == 0 {
+		return err
+	}
+	switch e := err.(type) {
+	case *baseError:
+		e.causes = append(e.causes, causes...)
+	case *MultiError:
+		e.causes = append(e.causes, causes...)
+	case *ValidationError:
+		e.causes = append(e.causes, causes...)
+	}
+	return err
+}
+
+var errMissingCode = errors1.New("缺少错误码")
+
+func newParseError(raw string, e error, causes ...error) *baseError {
+	return newError(ErrorSystemRPC, fmt.Sprintf("无法解析错误消息[%s],%v", raw, e), e, append([]error{e}, causes...), []Option{WithMessageKey("base.error.parse")})
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.33.0
+// 	protoc-gen-go v1.34.2
 // 	protoc        v5.27.1
 // source: encoder.proto
 
@@ -31,7 +31,7 @@ type LogBody struct {
 	Message    string          `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
 	Stack      string          `protobuf:"bytes,5,opt,name=stack,proto3" json:"stack,omitempty"`
 	Caller     *LogEntryCaller `protobuf:"bytes,6,opt,name=caller,proto3" json:"caller,omitempty"`
-	Fields     string          `protobuf:"bytes,7,opt,name=fields,proto3" json:"fields,omitempty"`
+	Fields     []*Field        `protobuf:"bytes,8,rep,name=fields,proto3" json:"fields,omitempty"`
 }
 
 func (x *LogBody) Reset() {
@@ -108,11 +108,11 @@ func (x *LogBody) GetCaller() *LogEntryCaller {
 	return nil
 }
 
-func (x *LogBody) GetFields() string {
+func (x *LogBody) GetFields() []*Field {
 	if x != nil {
 		return x.Fields
 	}
-	return ""
+	return nil
 }
 
 type LogEntryCaller struct {
@@ -121,7 +121,7 @@ type LogEntryCaller struct {
 	unknownFields protoimpl.UnknownFields
 
 	Defined bool `protobuf:"varint,1,opt,name=defined,proto3" json:"defined,omitempty"`
-	// PC       uintptr
+	//    	PC       uintptr
 	File     string `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
 	Line     int32  `protobuf:"varint,3,opt,name=line,proto3" json:"line,omitempty"`
 	Function string `protobuf:"bytes,4,opt,name=function,proto3" json:"function,omitempty"`
@@ -187,11 +187,325 @@ func (x *LogEntryCaller) GetFunction() string {
 	return ""
 }
 
+// Field 带类型的日志字段
+type Field struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Types that are assignable to Value:
+	//	*Field_IntValue
+	//	*Field_UintValue
+	//	*Field_DoubleValue
+	//	*Field_BoolValue
+	//	*Field_StringValue
+	//	*Field_BytesValue
+	//	*Field_JsonValue
+	//	*Field_DurationValue
+	//	*Field_TimeValue
+	//	*Field_ObjectValue
+	//	*Field_ArrayValue
+	Value isField_Value `protobuf_oneof:"value"`
+}
+
+func (x *Field) Reset() {
+	*x = Field{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Field) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Field) ProtoMessage() {}
+
+func (x *Field) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Field.ProtoReflect.Descriptor instead.
+func (*Field) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Field) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (m *Field) GetValue() isField_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *Field) GetIntValue() int64 {
+	if x, ok := x.GetValue().(*Field_IntValue); ok {
+		return x.IntValue
+	}
+	return 0
+}
+
+func (x *Field) GetUintValue() uint64 {
+	if x, ok := x.GetValue().(*Field_UintValue); ok {
+		return x.UintValue
+	}
+	return 0
+}
+
+func (x *Field) GetDoubleValue() float64 {
+	if x, ok := x.GetValue().(*Field_DoubleValue); ok {
+		return x.DoubleValue
+	}
+	return 0
+}
+
+func (x *Field) GetBoolValue() bool {
+	if x, ok := x.GetValue().(*Field_BoolValue); ok {
+		return x.BoolValue
+	}
+	return false
+}
+
+func (x *Field) GetStringValue() string {
+	if x, ok := x.GetValue().(*Field_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (x *Field) GetBytesValue() []byte {
+	if x, ok := x.GetValue().(*Field_BytesValue); ok {
+		return x.BytesValue
+	}
+	return nil
+}
+
+func (x *Field) GetJsonValue() string {
+	if x, ok := x.GetValue().(*Field_JsonValue); ok {
+		return x.JsonValue
+	}
+	return ""
+}
+
+func (x *Field) GetDurationValue() int64 {
+	if x, ok := x.GetValue().(*Field_DurationValue); ok {
+		return x.DurationValue
+	}
+	return 0
+}
+
+func (x *Field) GetTimeValue() int64 {
+	if x, ok := x.GetValue().(*Field_TimeValue); ok {
+		return x.TimeValue
+	}
+	return 0
+}
+
+func (x *Field) GetObjectValue() *FieldObject {
+	if x, ok := x.GetValue().(*Field_ObjectValue); ok {
+		return x.ObjectValue
+	}
+	return nil
+}
+
+func (x *Field) GetArrayValue() *FieldArray {
+	if x, ok := x.GetValue().(*Field_ArrayValue); ok {
+		return x.ArrayValue
+	}
+	return nil
+}
+
+type isField_Value interface {
+	isField_Value()
+}
+
+type Field_IntValue struct {
+	IntValue int64 `protobuf:"varint,2,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+type Field_UintValue struct {
+	UintValue uint64 `protobuf:"varint,3,opt,name=uint_value,json=uintValue,proto3,oneof"`
+}
+
+type Field_DoubleValue struct {
+	DoubleValue float64 `protobuf:"fixed64,4,opt,name=double_value,json=doubleValue,proto3,oneof"`
+}
+
+type Field_BoolValue struct {
+	BoolValue bool `protobuf:"varint,5,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+type Field_StringValue struct {
+	StringValue string `protobuf:"bytes,6,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type Field_BytesValue struct {
+	BytesValue []byte `protobuf:"bytes,7,opt,name=bytes_value,json=bytesValue,proto3,oneof"`
+}
+
+type Field_JsonValue struct {
+	// 无法用结构化类型表达的值(反射值)以 json 保存
+	JsonValue string `protobuf:"bytes,8,opt,name=json_value,json=jsonValue,proto3,oneof"`
+}
+
+type Field_DurationValue struct {
+	// 纳秒
+	DurationValue int64 `protobuf:"varint,9,opt,name=duration_value,json=durationValue,proto3,oneof"`
+}
+
+type Field_TimeValue struct {
+	// UnixNano
+	TimeValue int64 `protobuf:"varint,10,opt,name=time_value,json=timeValue,proto3,oneof"`
+}
+
+type Field_ObjectValue struct {
+	ObjectValue *FieldObject `protobuf:"bytes,11,opt,name=object_value,json=objectValue,proto3,oneof"`
+}
+
+type Field_ArrayValue struct {
+	ArrayValue *FieldArray `protobuf:"bytes,12,opt,name=array_value,json=arrayValue,proto3,oneof"`
+}
+
+func (*Field_IntValue) isField_Value() {}
+
+func (*Field_UintValue) isField_Value() {}
+
+func (*Field_DoubleValue) isField_Value() {}
+
+func (*Field_BoolValue) isField_Value() {}
+
+func (*Field_StringValue) isField_Value() {}
+
+func (*Field_BytesValue) isField_Value() {}
+
+func (*Field_JsonValue) isField_Value() {}
+
+func (*Field_DurationValue) isField_Value() {}
+
+func (*Field_TimeValue) isField_Value() {}
+
+func (*Field_ObjectValue) isField_Value() {}
+
+func (*Field_ArrayValue) isField_Value() {}
+
+// FieldObject 嵌套对象或命名空间
+type FieldObject struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fields []*Field `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+}
+
+func (x *FieldObject) Reset() {
+	*x = FieldObject{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldObject) ProtoMessage() {}
+
+func (x *FieldObject) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldObject.ProtoReflect.Descriptor instead.
+func (*FieldObject) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FieldObject) GetFields() []*Field {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// FieldArray 数组,元素的 key 为空
+type FieldArray struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Elements []*Field `protobuf:"bytes,1,rep,name=elements,proto3" json:"elements,omitempty"`
+}
+
+func (x *FieldArray) Reset() {
+	*x = FieldArray{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_encoder_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldArray) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldArray) ProtoMessage() {}
+
+func (x *FieldArray) ProtoReflect() protoreflect.Message {
+	mi := &file_encoder_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldArray.ProtoReflect.Descriptor instead.
+func (*FieldArray) Descriptor() ([]byte, []int) {
+	return file_encoder_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FieldArray) GetElements() []*Field {
+	if x != nil {
+		return x.Elements
+	}
+	return nil
+}
+
 var File_encoder_proto protoreflect.FileDescriptor
 
 var file_encoder_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x08, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x6c, 0x6f, 0x67, 0x22, 0xce, 0x01, 0x0a, 0x07, 0x4c, 0x6f,
+	0x08, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x6c, 0x6f, 0x67, 0x22, 0xe5, 0x01, 0x0a, 0x07, 0x4c, 0x6f,
 	0x67, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74,
 	0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12,
@@ -203,16 +517,55 @@ var file_encoder_proto_rawDesc = []byte{
 	0x12, 0x30, 0x0a, 0x06, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x18, 0x2e, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x6c, 0x6f, 0x67, 0x2e, 0x4c, 0x6f, 0x67, 0x45,
 	0x6e, 0x74, 0x72, 0x79, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x52, 0x06, 0x63, 0x61, 0x6c, 0x6c,
-	0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22, 0x6e, 0x0a, 0x0e, 0x4c, 0x6f,
-	0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x43, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07,
-	0x64, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64,
-	0x65, 0x66, 0x69, 0x6e, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69,
-	0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x1a,
-	0x0a, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x07, 0x5a, 0x05, 0x2e, 0x2f,
-	0x6c, 0x6f, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x72, 0x12, 0x27, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x6c, 0x6f, 0x67, 0x2e, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x4a, 0x04, 0x08, 0x07, 0x10,
+	0x08, 0x22, 0x6e, 0x0a, 0x0e, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x43, 0x61, 0x6c,
+	0x6c, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0xd0, 0x03, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1d, 0x0a,
+	0x09, 0x69, 0x6e, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x48, 0x00, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1f, 0x0a, 0x0a,
+	0x75, 0x69, 0x6e, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x48, 0x00, 0x52, 0x09, 0x75, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a,
+	0x0c, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0b, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x09, 0x62, 0x6f, 0x6f, 0x6c, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0b, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52,
+	0x0a, 0x62, 0x79, 0x74, 0x65, 0x73, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x6a,
+	0x73, 0x6f, 0x6e, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x09, 0x6a, 0x73, 0x6f, 0x6e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x27, 0x0a, 0x0e,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x0d, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x3a, 0x0a, 0x0c, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62,
+	0x61, 0x73, 0x65, 0x2e, 0x6c, 0x6f, 0x67, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x37, 0x0a, 0x0b, 0x61, 0x72, 0x72, 0x61, 0x79, 0x5f, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x6c,
+	0x6f, 0x67, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x41, 0x72, 0x72, 0x61, 0x79, 0x48, 0x00, 0x52,
+	0x0a, 0x61, 0x72, 0x72, 0x61, 0x79, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x22, 0x36, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x12, 0x27, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x6c, 0x6f, 0x67, 0x2e, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22, 0x39, 0x0a, 0x0a,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x41, 0x72, 0x72, 0x61, 0x79, 0x12, 0x2b, 0x0a, 0x08, 0x65, 0x6c,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62,
+	0x61, 0x73, 0x65, 0x2e, 0x6c, 0x6f, 0x67, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x08, 0x65,
+	0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x42, 0x1e, 0x5a, 0x1c, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6f, 0x66, 0x66, 0x65, 0x65, 0x68, 0x63, 0x2f, 0x62,
+	0x61, 0x73, 0x65, 0x2f, 0x6c, 0x6f, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -227,18 +580,26 @@ func file_encoder_proto_rawDescGZIP() []byte {
 	return file_encoder_proto_rawDescData
 }
 
-var file_encoder_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
-var file_encoder_proto_goTypes = []interface{}{
+var file_encoder_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_encoder_proto_goTypes = []any{
 	(*LogBody)(nil),        // 0: base.log.LogBody
 	(*LogEntryCaller)(nil), // 1: base.log.LogEntryCaller
+	(*Field)(nil),          // 2: base.log.Field
+	(*FieldObject)(nil),    // 3: base.log.FieldObject
+	(*FieldArray)(nil),     // 4: base.log.FieldArray
 }
 var file_encoder_proto_depIdxs = []int32{
 	1, // 0: base.log.LogBody.caller:type_name -> base.log.LogEntryCaller
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 1: base.log.LogBody.fields:type_name -> base.log.Field
+	3, // 2: base.log.Field.object_value:type_name -> base.log.FieldObject
+	4, // 3: base.log.Field.array_value:type_name -> base.log.FieldArray
+	2, // 4: base.log.FieldObject.fields:type_name -> base.log.Field
+	2, // 5: base.log.FieldArray.elements:type_name -> base.log.Field
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_encoder_proto_init() }
@@ -247,7 +608,7 @@ func file_encoder_proto_init() {
 		return
 	}
 	if !protoimpl.UnsafeEnabled {
-		file_encoder_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+		file_encoder_proto_msgTypes[0].Exporter = func(v any, i int) any {
 			switch v := v.(*LogBody); i {
 			case 0:
 				return &v.state
@@ -259,7 +620,7 @@ func file_encoder_proto_init() {
 				return nil
 			}
 		}
-		file_encoder_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+		file_encoder_proto_msgTypes[1].Exporter = func(v any, i int) any {
 			switch v := v.(*LogEntryCaller); i {
 			case 0:
 				return &v.state
@@ -271,6 +632,55 @@ func file_encoder_proto_init() {
 				return nil
 			}
 		}
+		file_encoder_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*Field); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*FieldObject); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_encoder_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*FieldArray); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_encoder_proto_msgTypes[2].OneofWrappers = []any{
+		(*Field_IntValue)(nil),
+		(*Field_UintValue)(nil),
+		(*Field_DoubleValue)(nil),
+		(*Field_BoolValue)(nil),
+		(*Field_StringValue)(nil),
+		(*Field_BytesValue)(nil),
+		(*Field_JsonValue)(nil),
+		(*Field_DurationValue)(nil),
+		(*Field_TimeValue)(nil),
+		(*Field_ObjectValue)(nil),
+		(*Field_ArrayValue)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -278,7 +688,7 @@ func file_encoder_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_encoder_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
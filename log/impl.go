@@ -61,6 +61,7 @@ func newService() Service {
 		EnableConsole: true,
 		EnableColor:   true,
 		EnableSampler: true,
+		Format:        FormatJSON,
 	}
 	viper.SetDefault("logger", defaultConfig)
 	logFileWrite := &lumberjack.Logger{
@@ -77,6 +78,7 @@ func newService() Service {
 	impl := &serviceImpl{
 		level:         zap.NewAtomicLevel(),
 		logFileWrite:  logFileWrite,
+		pbFileWrite:   newPBWrite(defaultConfig.FileConfig),
 		rootLogger:    rootLogger,
 		conf:          defaultConfig,
 		_logWritePipe: newLogWritePipe(defaultLogHistoryCap),
@@ -95,6 +97,7 @@ type serviceImpl struct {
 	baseFields     []zap.Field
 	configHash     string
 	logFileWrite   *lumberjack.Logger
+	pbFileWrite    PBWrite
 	conf           *Config
 	_logWritePipe  *logWritePipe
 }
@@ -183,9 +186,25 @@ func (impl *serviceImpl) LoadConfig() {
 		if fileLogConfig.Compress != impl.logFileWrite.Compress {
 			impl.logFileWrite.Compress = fileLogConfig.Compress
 		}
-		impl.logFileWrite.Rotate()
-		encoder := zapcore.NewJSONEncoder(newEncodeConfig())
-		core := zapcore.NewCore(encoder, zapcore.AddSync(impl.logFileWrite), impl.level)
+		var fileWrite zapcore.WriteSyncer
+		if strings.ToLower(conf.Format) == FormatProtobuf {
+			// protobuf 日志需要逐条加长度前缀,使用独立的写入器
+			impl.pbFileWrite.SetConfig(FileLogConfig{
+				FileName:   impl.logFileWrite.Filename,
+				Enable:     true,
+				Maxsize:    impl.logFileWrite.MaxSize,
+				MaxBackups: impl.logFileWrite.MaxBackups,
+				MaxAge:     impl.logFileWrite.MaxAge,
+				Compress:   impl.logFileWrite.Compress,
+			})
+			impl.pbFileWrite.Rotate()
+			fileWrite = impl.pbFileWrite
+		} else {
+			impl.logFileWrite.Rotate()
+			fileWrite = zapcore.AddSync(impl.logFileWrite)
+		}
+		encoder := newFileEncoder(conf.Format)
+		core := zapcore.NewCore(encoder, fileWrite, impl.level)
 		if conf.EnableSampler {
 			core = zapcore.NewSamplerWithOptions(core, time.Second*5, 100, 10)
 		}
@@ -1,16 +1,25 @@
 package log
 
 import (
-	"encoding/base64"
 	"encoding/json"
-	"google.golang.org/protobuf/proto"
-	"math"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
-	"unicode/utf8"
 
+	"go.uber.org/zap"
 	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+const (
+	// FormatJSON 文件日志使用 json 格式
+	FormatJSON = "json"
+	// FormatConsole 文件日志使用控制台文本格式
+	FormatConsole = "console"
+	// FormatProtobuf 文件日志使用 LogBody 二进制格式,每条记录带 varint 长度前缀
+	FormatProtobuf = "protobuf"
 )
 
 var (
@@ -19,226 +28,100 @@ var (
 	get = _pool.Get
 )
 
+func init() {
+	_ = zap.RegisterEncoder(FormatProtobuf, func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return newPBEncoder(cfg), nil
+	})
+}
+
+// pbEncoder 将日志编码为 LogBody,字段保留类型信息
 type pbEncoder struct {
 	*zapcore.EncoderConfig
-	buf            *buffer.Buffer
-	spaced         bool // include spaces after colons and commas
-	openNamespaces int
-
-	// for encoding generic values by reflection
-	reflectBuf *buffer.Buffer
-	reflectEnc *json.Encoder
+	root *FieldObject
+	// namespaces 为当前打开的命名空间,之后的字段写入最内层的命名空间
+	namespaces []*FieldObject
 }
 
-func newPBEncoder(cfg zapcore.EncoderConfig, spaced bool) *pbEncoder {
+func newPBEncoder(cfg zapcore.EncoderConfig) *pbEncoder {
 	return &pbEncoder{
 		EncoderConfig: &cfg,
-		buf:           get(),
-		spaced:        spaced,
+		root:          &FieldObject{},
+	}
+}
+
+func (enc *pbEncoder) addField(key string, value isField_Value) {
+	target := enc.root
+	if n := len(enc.namespaces); n > 0 {
+		target = enc.namespaces[n-1]
 	}
+	target.Fields = append(target.Fields, &Field{Key: key, Value: value})
 }
 
 func (enc *pbEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
-	enc.addKey(key)
-	return enc.AppendArray(arr)
+	value, err := marshalPBArray(enc.EncoderConfig, arr)
+	enc.addField(key, value)
+	return err
 }
 
 func (enc *pbEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
-	enc.addKey(key)
-	return enc.AppendObject(obj)
+	value, err := marshalPBObject(enc.EncoderConfig, obj)
+	enc.addField(key, value)
+	return err
 }
 
 func (enc *pbEncoder) AddBinary(key string, val []byte) {
-	enc.AddString(key, base64.StdEncoding.EncodeToString(val))
+	enc.addField(key, &Field_BytesValue{BytesValue: append([]byte(nil), val...)})
 }
 
 func (enc *pbEncoder) AddByteString(key string, val []byte) {
-	enc.addKey(key)
-	enc.AppendByteString(val)
+	enc.addField(key, &Field_StringValue{StringValue: string(val)})
 }
 
 func (enc *pbEncoder) AddBool(key string, val bool) {
-	enc.addKey(key)
-	enc.AppendBool(val)
+	enc.addField(key, &Field_BoolValue{BoolValue: val})
 }
 
 func (enc *pbEncoder) AddComplex128(key string, val complex128) {
-	enc.addKey(key)
-	enc.AppendComplex128(val)
+	enc.addField(key, pbComplexValue(val))
 }
 
 func (enc *pbEncoder) AddDuration(key string, val time.Duration) {
-	enc.addKey(key)
-	enc.AppendDuration(val)
+	enc.addField(key, &Field_DurationValue{DurationValue: int64(val)})
 }
 
 func (enc *pbEncoder) AddFloat64(key string, val float64) {
-	enc.addKey(key)
-	enc.AppendFloat64(val)
+	enc.addField(key, &Field_DoubleValue{DoubleValue: val})
 }
 
 func (enc *pbEncoder) AddInt64(key string, val int64) {
-	enc.addKey(key)
-	enc.AppendInt64(val)
-}
-
-func (enc *pbEncoder) resetReflectBuf() {
-	if enc.reflectBuf == nil {
-		enc.reflectBuf = get()
-		enc.reflectEnc = json.NewEncoder(enc.reflectBuf)
-
-		// For consistency with our custom JSON encoder.
-		enc.reflectEnc.SetEscapeHTML(false)
-	} else {
-		enc.reflectBuf.Reset()
-	}
-}
-
-var nullLiteralBytes = []byte("null")
-
-// Only invoke the standard JSON encoder if there is actually something to
-// encode; otherwise write JSON null literal directly.
-func (enc *pbEncoder) encodeReflected(obj interface{}) ([]byte, error) {
-	if obj == nil {
-		return nullLiteralBytes, nil
-	}
-	enc.resetReflectBuf()
-	if err := enc.reflectEnc.Encode(obj); err != nil {
-		return nil, err
-	}
-	enc.reflectBuf.TrimNewline()
-	return enc.reflectBuf.Bytes(), nil
+	enc.addField(key, &Field_IntValue{IntValue: val})
 }
 
 func (enc *pbEncoder) AddReflected(key string, obj interface{}) error {
-	valueBytes, err := enc.encodeReflected(obj)
+	value, err := pbReflectedValue(obj)
 	if err != nil {
 		return err
 	}
-	enc.addKey(key)
-	_, err = enc.buf.Write(valueBytes)
-	return err
+	enc.addField(key, value)
+	return nil
 }
 
 func (enc *pbEncoder) OpenNamespace(key string) {
-	enc.addKey(key)
-	enc.buf.AppendByte('{')
-	enc.openNamespaces++
+	namespace := &FieldObject{}
+	enc.addField(key, &Field_ObjectValue{ObjectValue: namespace})
+	enc.namespaces = append(enc.namespaces, namespace)
 }
 
 func (enc *pbEncoder) AddString(key, val string) {
-	enc.addKey(key)
-	enc.AppendString(val)
+	enc.addField(key, &Field_StringValue{StringValue: val})
 }
 
 func (enc *pbEncoder) AddTime(key string, val time.Time) {
-	enc.addKey(key)
-	enc.AppendTime(val)
+	enc.addField(key, &Field_TimeValue{TimeValue: val.UnixNano()})
 }
 
 func (enc *pbEncoder) AddUint64(key string, val uint64) {
-	enc.addKey(key)
-	enc.AppendUint64(val)
-}
-
-func (enc *pbEncoder) AppendArray(arr zapcore.ArrayMarshaler) error {
-	enc.addElementSeparator()
-	enc.buf.AppendByte('[')
-	err := arr.MarshalLogArray(enc)
-	enc.buf.AppendByte(']')
-	return err
-}
-
-func (enc *pbEncoder) AppendObject(obj zapcore.ObjectMarshaler) error {
-	enc.addElementSeparator()
-	enc.buf.AppendByte('{')
-	err := obj.MarshalLogObject(enc)
-	enc.buf.AppendByte('}')
-	return err
-}
-
-func (enc *pbEncoder) AppendBool(val bool) {
-	enc.addElementSeparator()
-	enc.buf.AppendBool(val)
-}
-
-func (enc *pbEncoder) AppendByteString(val []byte) {
-	enc.addElementSeparator()
-	enc.buf.AppendByte('"')
-	enc.safeAddByteString(val)
-	enc.buf.AppendByte('"')
-}
-
-func (enc *pbEncoder) AppendComplex128(val complex128) {
-	enc.addElementSeparator()
-	// Cast to a platform-independent, fixed-size type.
-	r, i := float64(real(val)), float64(imag(val))
-	enc.buf.AppendByte('"')
-	// Because we're always in a quoted string, we can use strconv without
-	// special-casing NaN and +/-Inf.
-	enc.buf.AppendFloat(r, 64)
-	enc.buf.AppendByte('+')
-	enc.buf.AppendFloat(i, 64)
-	enc.buf.AppendByte('i')
-	enc.buf.AppendByte('"')
-}
-
-func (enc *pbEncoder) AppendDuration(val time.Duration) {
-	cur := enc.buf.Len()
-	if e := enc.EncodeDuration; e != nil {
-		e(val, enc)
-	}
-	if cur == enc.buf.Len() {
-		// User-supplied EncodeDuration is a no-op. Fall back to nanoseconds to keep
-		// JSON valid.
-		enc.AppendInt64(int64(val))
-	}
-}
-
-func (enc *pbEncoder) AppendInt64(val int64) {
-	enc.addElementSeparator()
-	enc.buf.AppendInt(val)
-}
-
-func (enc *pbEncoder) AppendReflected(val interface{}) error {
-	valueBytes, err := enc.encodeReflected(val)
-	if err != nil {
-		return err
-	}
-	enc.addElementSeparator()
-	_, err = enc.buf.Write(valueBytes)
-	return err
-}
-
-func (enc *pbEncoder) AppendString(val string) {
-	enc.addElementSeparator()
-	enc.buf.AppendByte('"')
-	enc.safeAddString(val)
-	enc.buf.AppendByte('"')
-}
-
-func (enc *pbEncoder) AppendTimeLayout(time time.Time, layout string) {
-	enc.addElementSeparator()
-	enc.buf.AppendByte('"')
-	enc.buf.AppendTime(time, layout)
-	enc.buf.AppendByte('"')
-}
-
-func (enc *pbEncoder) AppendTime(val time.Time) {
-	cur := enc.buf.Len()
-	if e := enc.EncodeTime; e != nil {
-		e(val, enc)
-	}
-	if cur == enc.buf.Len() {
-		// User-supplied EncodeTime is a no-op. Fall back to nanos since epoch to keep
-		// output JSON valid.
-		enc.AppendInt64(val.UnixNano())
-	}
-}
-
-func (enc *pbEncoder) AppendUint64(val uint64) {
-	enc.addElementSeparator()
-	enc.buf.AppendUint(val)
+	enc.addField(key, &Field_UintValue{UintValue: val})
 }
 
 func (enc *pbEncoder) AddComplex64(k string, v complex64) { enc.AddComplex128(k, complex128(v)) }
@@ -252,37 +135,31 @@ func (enc *pbEncoder) AddUint32(k string, v uint32)       { enc.AddUint64(k, uin
 func (enc *pbEncoder) AddUint16(k string, v uint16)       { enc.AddUint64(k, uint64(v)) }
 func (enc *pbEncoder) AddUint8(k string, v uint8)         { enc.AddUint64(k, uint64(v)) }
 func (enc *pbEncoder) AddUintptr(k string, v uintptr)     { enc.AddUint64(k, uint64(v)) }
-func (enc *pbEncoder) AppendComplex64(v complex64)        { enc.AppendComplex128(complex128(v)) }
-func (enc *pbEncoder) AppendFloat64(v float64)            { enc.appendFloat(v, 64) }
-func (enc *pbEncoder) AppendFloat32(v float32)            { enc.appendFloat(float64(v), 32) }
-func (enc *pbEncoder) AppendInt(v int)                    { enc.AppendInt64(int64(v)) }
-func (enc *pbEncoder) AppendInt32(v int32)                { enc.AppendInt64(int64(v)) }
-func (enc *pbEncoder) AppendInt16(v int16)                { enc.AppendInt64(int64(v)) }
-func (enc *pbEncoder) AppendInt8(v int8)                  { enc.AppendInt64(int64(v)) }
-func (enc *pbEncoder) AppendUint(v uint)                  { enc.AppendUint64(uint64(v)) }
-func (enc *pbEncoder) AppendUint32(v uint32)              { enc.AppendUint64(uint64(v)) }
-func (enc *pbEncoder) AppendUint16(v uint16)              { enc.AppendUint64(uint64(v)) }
-func (enc *pbEncoder) AppendUint8(v uint8)                { enc.AppendUint64(uint64(v)) }
-func (enc *pbEncoder) AppendUintptr(v uintptr)            { enc.AppendUint64(uint64(v)) }
 
 func (enc *pbEncoder) Clone() zapcore.Encoder {
-	clone := enc.clone()
-	clone.buf.Write(enc.buf.Bytes())
-	return clone
+	return enc.clone()
 }
 
+// clone 复制根对象以及打开的命名空间链,已经写入的 Field 不再修改,可以共享指针
 func (enc *pbEncoder) clone() *pbEncoder {
 	clone := getPBEncoder()
 	clone.EncoderConfig = enc.EncoderConfig
-	clone.spaced = enc.spaced
-	clone.openNamespaces = enc.openNamespaces
-	clone.buf = get()
+	clone.root = &FieldObject{Fields: append([]*Field(nil), enc.root.Fields...)}
+	parent := clone.root
+	for _, namespace := range enc.namespaces {
+		// 打开的命名空间总是父对象的最后一个字段
+		last := parent.Fields[len(parent.Fields)-1]
+		copied := &FieldObject{Fields: append([]*Field(nil), namespace.Fields...)}
+		parent.Fields[len(parent.Fields)-1] = &Field{Key: last.Key, Value: &Field_ObjectValue{ObjectValue: copied}}
+		clone.namespaces = append(clone.namespaces, copied)
+		parent = copied
+	}
 	return clone
 }
 
 func (enc *pbEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
-	final := getPBEncoder()
-	// TODO 此处需要处理一下
+	final := enc.clone()
+	defer putPBEncoder(final)
 	body := &LogBody{
 		Level:      int32(ent.Level),
 		Time:       ent.Time.UnixNano(),
@@ -299,188 +176,127 @@ func (enc *pbEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*b
 		}
 	}
 	addFields(final, fields)
-	if final.LineEnding != "" {
-		final.buf.AppendString(final.LineEnding)
-	} else {
-		final.buf.AppendString(zapcore.DefaultLineEnding)
-	}
-	body.Fields = final.buf.String()
-	putPBEncoder(final)
-	buf := &buffer.Buffer{}
-	data, err := proto.Marshal(body)
-	if err != nil {
+	body.Fields = final.root.Fields
+	// 每条记录带 varint 长度前缀,任意 WriteSyncer 写出的文件都可以用 ReadLogBodies 读取
+	buf := get()
+	if _, err := protodelim.MarshalTo(buf, body); err != nil {
+		buf.Free()
 		return nil, err
 	}
-	buf.Write(data)
 	return buf, nil
 }
 
-func (enc *pbEncoder) buildFields(extra []zapcore.Field) {
-	context := enc.Clone().(*pbEncoder)
-	defer func() {
-		// putJSONEncoder assumes the buffer is still used, but we write out the buffer so
-		// we can free it.
-		context.buf.Free()
-		putPBEncoder(context)
-	}()
-
-	addFields(context, extra)
-	// context.closeOpenNamespaces()
-	// if context.buf.Len() == 0 {
-	// 	return
-	// }
-	//
-	// c.addSeparatorIfNecessary(line)
-	// line.AppendByte('{')
-	// line.Write(context.buf.Bytes())
-	// line.AppendByte('}')
-}
-
-func (enc *pbEncoder) truncate() {
-	enc.buf.Reset()
-}
-func (enc *pbEncoder) addKey(key string) {
-	enc.addElementSeparator()
-	enc.buf.AppendByte('"')
-	enc.safeAddString(key)
-	enc.buf.AppendByte('"')
-	enc.buf.AppendByte(':')
-	if enc.spaced {
-		enc.buf.AppendByte(' ')
-	}
+var _pbPool = sync.Pool{New: func() interface{} {
+	return &pbEncoder{}
+}}
+
+func getPBEncoder() *pbEncoder {
+	return _pbPool.Get().(*pbEncoder)
 }
 
-func (enc *pbEncoder) addElementSeparator() {
-	last := enc.buf.Len() - 1
-	if last < 0 {
-		return
-	}
-	switch enc.buf.Bytes()[last] {
-	case '{', '[', ':', ',', ' ':
-		return
-	default:
-		enc.buf.AppendByte(',')
-		if enc.spaced {
-			enc.buf.AppendByte(' ')
-		}
-	}
+func putPBEncoder(enc *pbEncoder) {
+	enc.EncoderConfig = nil
+	enc.root = nil
+	enc.namespaces = nil
+	_pbPool.Put(enc)
 }
 
-func (enc *pbEncoder) appendFloat(val float64, bitSize int) {
-	enc.addElementSeparator()
-	switch {
-	case math.IsNaN(val):
-		enc.buf.AppendString(`"NaN"`)
-	case math.IsInf(val, 1):
-		enc.buf.AppendString(`"+Inf"`)
-	case math.IsInf(val, -1):
-		enc.buf.AppendString(`"-Inf"`)
-	default:
-		enc.buf.AppendFloat(val, bitSize)
+func addFields(enc zapcore.ObjectEncoder, fields []zapcore.Field) {
+	for i := range fields {
+		fields[i].AddTo(enc)
 	}
 }
 
-// safeAddString JSON-escapes a string and appends it to the internal buffer.
-// Unlike the standard library's encoder, it doesn't attempt to protect the
-// user from browser vulnerabilities or JSONP-related problems.
-func (enc *pbEncoder) safeAddString(s string) {
-	for i := 0; i < len(s); {
-		if enc.tryAddRuneSelf(s[i]) {
-			i++
-			continue
-		}
-		r, size := utf8.DecodeRuneInString(s[i:])
-		if enc.tryAddRuneError(r, size) {
-			i++
-			continue
-		}
-		enc.buf.AppendString(s[i : i+size])
-		i += size
-	}
+// pbArrayEncoder 收集数组元素
+type pbArrayEncoder struct {
+	*zapcore.EncoderConfig
+	elements []*Field
 }
 
-// safeAddByteString is no-alloc equivalent of safeAddString(string(s)) for s []byte.
-func (enc *pbEncoder) safeAddByteString(s []byte) {
-	for i := 0; i < len(s); {
-		if enc.tryAddRuneSelf(s[i]) {
-			i++
-			continue
-		}
-		r, size := utf8.DecodeRune(s[i:])
-		if enc.tryAddRuneError(r, size) {
-			i++
-			continue
-		}
-		enc.buf.Write(s[i : i+size])
-		i += size
-	}
+func marshalPBArray(cfg *zapcore.EncoderConfig, arr zapcore.ArrayMarshaler) (*Field_ArrayValue, error) {
+	enc := &pbArrayEncoder{EncoderConfig: cfg}
+	err := arr.MarshalLogArray(enc)
+	return &Field_ArrayValue{ArrayValue: &FieldArray{Elements: enc.elements}}, err
 }
 
-// tryAddRuneSelf appends b if it is valid UTF-8 character represented in a single byte.
-func (enc *pbEncoder) tryAddRuneSelf(b byte) bool {
-	if b >= utf8.RuneSelf {
-		return false
-	}
-	if 0x20 <= b && b != '\\' && b != '"' {
-		enc.buf.AppendByte(b)
-		return true
-	}
-	switch b {
-	case '\\', '"':
-		enc.buf.AppendByte('\\')
-		enc.buf.AppendByte(b)
-	case '\n':
-		enc.buf.AppendByte('\\')
-		enc.buf.AppendByte('n')
-	case '\r':
-		enc.buf.AppendByte('\\')
-		enc.buf.AppendByte('r')
-	case '\t':
-		enc.buf.AppendByte('\\')
-		enc.buf.AppendByte('t')
-	default:
-		// Encode bytes < 0x20, except for the escape sequences above.
-		enc.buf.AppendString(`\u00`)
-		enc.buf.AppendByte(_hex[b>>4])
-		enc.buf.AppendByte(_hex[b&0xF])
-	}
-	return true
+func marshalPBObject(cfg *zapcore.EncoderConfig, obj zapcore.ObjectMarshaler) (*Field_ObjectValue, error) {
+	enc := &pbEncoder{EncoderConfig: cfg, root: &FieldObject{}}
+	err := obj.MarshalLogObject(enc)
+	return &Field_ObjectValue{ObjectValue: enc.root}, err
 }
 
-func (enc *pbEncoder) tryAddRuneError(r rune, size int) bool {
-	if r == utf8.RuneError && size == 1 {
-		enc.buf.AppendString(`\ufffd`)
-		return true
-	}
-	return false
+func pbComplexValue(val complex128) *Field_StringValue {
+	return &Field_StringValue{StringValue: strconv.FormatComplex(val, 'g', -1, 128)}
 }
 
-// For JSON-escaping; see jsonEncoder.safeAddString below.
-const _hex = "0123456789abcdef"
+func pbReflectedValue(obj interface{}) (*Field_JsonValue, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &Field_JsonValue{JsonValue: string(data)}, nil
+}
 
-var _pbPool = sync.Pool{New: func() interface{} {
-	return &pbEncoder{}
-}}
+func (enc *pbArrayEncoder) append(value isField_Value) {
+	enc.elements = append(enc.elements, &Field{Value: value})
+}
 
-func getPBEncoder() *pbEncoder {
-	return _pbPool.Get().(*pbEncoder)
+func (enc *pbArrayEncoder) AppendArray(arr zapcore.ArrayMarshaler) error {
+	value, err := marshalPBArray(enc.EncoderConfig, arr)
+	enc.append(value)
+	return err
 }
 
-func putPBEncoder(enc *pbEncoder) {
-	if enc.reflectBuf != nil {
-		enc.reflectBuf.Free()
-	}
-	enc.EncoderConfig = nil
-	enc.buf = nil
-	enc.spaced = false
-	enc.openNamespaces = 0
-	enc.reflectBuf = nil
-	enc.reflectEnc = nil
-	_pbPool.Put(enc)
+func (enc *pbArrayEncoder) AppendObject(obj zapcore.ObjectMarshaler) error {
+	value, err := marshalPBObject(enc.EncoderConfig, obj)
+	enc.append(value)
+	return err
 }
 
-func addFields(enc zapcore.ObjectEncoder, fields []zapcore.Field) {
-	for i := range fields {
-		fields[i].AddTo(enc)
+func (enc *pbArrayEncoder) AppendReflected(val interface{}) error {
+	value, err := pbReflectedValue(val)
+	if err != nil {
+		return err
+	}
+	enc.append(value)
+	return nil
+}
+
+func (enc *pbArrayEncoder) AppendBool(v bool) { enc.append(&Field_BoolValue{BoolValue: v}) }
+func (enc *pbArrayEncoder) AppendByteString(v []byte) {
+	enc.append(&Field_StringValue{StringValue: string(v)})
+}
+func (enc *pbArrayEncoder) AppendComplex128(v complex128) { enc.append(pbComplexValue(v)) }
+func (enc *pbArrayEncoder) AppendComplex64(v complex64)   { enc.append(pbComplexValue(complex128(v))) }
+func (enc *pbArrayEncoder) AppendDuration(v time.Duration) {
+	enc.append(&Field_DurationValue{DurationValue: int64(v)})
+}
+func (enc *pbArrayEncoder) AppendFloat64(v float64) { enc.append(&Field_DoubleValue{DoubleValue: v}) }
+func (enc *pbArrayEncoder) AppendFloat32(v float32) { enc.AppendFloat64(float64(v)) }
+func (enc *pbArrayEncoder) AppendInt64(v int64)     { enc.append(&Field_IntValue{IntValue: v}) }
+func (enc *pbArrayEncoder) AppendInt(v int)         { enc.AppendInt64(int64(v)) }
+func (enc *pbArrayEncoder) AppendInt32(v int32)     { enc.AppendInt64(int64(v)) }
+func (enc *pbArrayEncoder) AppendInt16(v int16)     { enc.AppendInt64(int64(v)) }
+func (enc *pbArrayEncoder) AppendInt8(v int8)       { enc.AppendInt64(int64(v)) }
+func (enc *pbArrayEncoder) AppendString(v string)   { enc.append(&Field_StringValue{StringValue: v}) }
+func (enc *pbArrayEncoder) AppendTime(v time.Time) {
+	enc.append(&Field_TimeValue{TimeValue: v.UnixNano()})
+}
+func (enc *pbArrayEncoder) AppendUint64(v uint64)   { enc.append(&Field_UintValue{UintValue: v}) }
+func (enc *pbArrayEncoder) AppendUint(v uint)       { enc.AppendUint64(uint64(v)) }
+func (enc *pbArrayEncoder) AppendUint32(v uint32)   { enc.AppendUint64(uint64(v)) }
+func (enc *pbArrayEncoder) AppendUint16(v uint16)   { enc.AppendUint64(uint64(v)) }
+func (enc *pbArrayEncoder) AppendUint8(v uint8)     { enc.AppendUint64(uint64(v)) }
+func (enc *pbArrayEncoder) AppendUintptr(v uintptr) { enc.AppendUint64(uint64(v)) }
+
+// newFileEncoder 根据配置的格式创建文件日志的编码器
+func newFileEncoder(format string) zapcore.Encoder {
+	switch strings.ToLower(format) {
+	case FormatProtobuf:
+		return newPBEncoder(newEncodeConfig())
+	case FormatConsole:
+		return zapcore.NewConsoleEncoder(newEncodeConfig())
+	default:
+		return zapcore.NewJSONEncoder(newEncodeConfig())
 	}
 }
@@ -0,0 +1,90 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+func TestPBEncoder(t *testing.T) {
+	enc := newPBEncoder(newEncodeConfig())
+	enc.AddString("service", "order")
+	ent := zapcore.Entry{Level: zapcore.WarnLevel, Time: time.Now(), LoggerName: "test", Message: "hello"}
+	buf, err := enc.EncodeEntry(ent, []zapcore.Field{
+		zap.Int64("count", 3),
+		zap.Bool("ok", true),
+		zap.Namespace("req"),
+		zap.String("id", "abc"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Free()
+	body := &LogBody{}
+	if err := protodelim.UnmarshalFrom(bytes.NewReader(buf.Bytes()), body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Message != "hello" || body.Level != int32(zapcore.WarnLevel) || body.LoggerName != "test" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+	if len(body.Fields) != 4 {
+		t.Fatalf("unexpected fields: %v", body.Fields)
+	}
+	if body.Fields[0].GetStringValue() != "order" || body.Fields[1].GetIntValue() != 3 || !body.Fields[2].GetBoolValue() {
+		t.Fatalf("unexpected fields: %v", body.Fields)
+	}
+	namespace := body.Fields[3].GetObjectValue()
+	if body.Fields[3].Key != "req" || namespace == nil || namespace.Fields[0].Key != "id" {
+		t.Fatalf("unexpected namespace: %v", body.Fields[3])
+	}
+}
+
+func TestPBEncoderTypedFields(t *testing.T) {
+	enc := newPBEncoder(newEncodeConfig())
+	buf, err := enc.EncodeEntry(zapcore.Entry{Time: time.Now()}, []zapcore.Field{
+		zap.Duration("cost", time.Second),
+		zap.Time("at", time.Unix(0, 42)),
+		zap.Ints("ids", []int{1, 2}),
+		zap.Object("user", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("name", "coffee")
+			return enc.AddArray("tags", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+				arr.AppendString("a")
+				return nil
+			}))
+		})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Free()
+	body := &LogBody{}
+	if err := protodelim.UnmarshalFrom(bytes.NewReader(buf.Bytes()), body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Fields[0].GetDurationValue() != int64(time.Second) || body.Fields[1].GetTimeValue() != 42 {
+		t.Fatalf("unexpected fields: %v", body.Fields)
+	}
+	ids := body.Fields[2].GetArrayValue()
+	if ids == nil || len(ids.Elements) != 2 || ids.Elements[1].GetIntValue() != 2 {
+		t.Fatalf("unexpected array: %v", body.Fields[2])
+	}
+	user := body.Fields[3].GetObjectValue()
+	if user == nil || user.Fields[0].GetStringValue() != "coffee" || user.Fields[1].GetArrayValue().Elements[0].GetStringValue() != "a" {
+		t.Fatalf("unexpected object: %v", body.Fields[3])
+	}
+}
+
+func TestPBEncoderCloneNamespace(t *testing.T) {
+	enc := newPBEncoder(newEncodeConfig())
+	enc.OpenNamespace("req")
+	enc.AddString("id", "1")
+	clone := enc.Clone()
+	clone.AddString("extra", "x")
+	if len(enc.root.Fields[0].GetObjectValue().Fields) != 1 {
+		t.Fatal("clone must not modify the original namespace")
+	}
+}
@@ -0,0 +1,121 @@
+package log
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewEncoder 创建与服务日志一致的编码器,format 支持 json、console、protobuf
+func NewEncoder(format string) zapcore.Encoder {
+	return newFileEncoder(format)
+}
+
+// RenderLogBody 使用指定编码器重新渲染一条二进制日志
+func RenderLogBody(enc zapcore.Encoder, body *LogBody) (*buffer.Buffer, error) {
+	ent, fields := body.Entry()
+	return enc.EncodeEntry(ent, fields)
+}
+
+// Entry 将 LogBody 还原为 zap 的日志条目和字段
+func (x *LogBody) Entry() (zapcore.Entry, []zapcore.Field) {
+	ent := zapcore.Entry{
+		Level:      zapcore.Level(x.GetLevel()),
+		Time:       time.Unix(0, x.GetTime()),
+		LoggerName: x.GetLoggerName(),
+		Message:    x.GetMessage(),
+		Stack:      x.GetStack(),
+	}
+	if caller := x.GetCaller(); caller != nil {
+		ent.Caller = zapcore.EntryCaller{
+			Defined:  caller.GetDefined(),
+			File:     caller.GetFile(),
+			Line:     int(caller.GetLine()),
+			Function: caller.GetFunction(),
+		}
+	}
+	fields := make([]zapcore.Field, 0, len(x.GetFields()))
+	for _, field := range x.GetFields() {
+		fields = append(fields, field.ZapField())
+	}
+	return ent, fields
+}
+
+// ZapField 将 Field 还原为 zap 字段
+func (x *Field) ZapField() zapcore.Field {
+	key := x.GetKey()
+	switch value := x.GetValue().(type) {
+	case *Field_IntValue:
+		return zap.Int64(key, value.IntValue)
+	case *Field_UintValue:
+		return zap.Uint64(key, value.UintValue)
+	case *Field_DoubleValue:
+		return zap.Float64(key, value.DoubleValue)
+	case *Field_BoolValue:
+		return zap.Bool(key, value.BoolValue)
+	case *Field_StringValue:
+		return zap.String(key, value.StringValue)
+	case *Field_BytesValue:
+		return zap.Binary(key, value.BytesValue)
+	case *Field_JsonValue:
+		return zap.Reflect(key, json.RawMessage(value.JsonValue))
+	case *Field_DurationValue:
+		return zap.Duration(key, time.Duration(value.DurationValue))
+	case *Field_TimeValue:
+		return zap.Time(key, time.Unix(0, value.TimeValue))
+	case *Field_ObjectValue:
+		return zap.Object(key, value.ObjectValue)
+	case *Field_ArrayValue:
+		return zap.Array(key, value.ArrayValue)
+	default:
+		return zap.Skip()
+	}
+}
+
+// MarshalLogObject 实现 zapcore.ObjectMarshaler
+func (x *FieldObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, field := range x.GetFields() {
+		field.ZapField().AddTo(enc)
+	}
+	return nil
+}
+
+// MarshalLogArray 实现 zapcore.ArrayMarshaler
+func (x *FieldArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, element := range x.GetElements() {
+		switch value := element.GetValue().(type) {
+		case *Field_IntValue:
+			enc.AppendInt64(value.IntValue)
+		case *Field_UintValue:
+			enc.AppendUint64(value.UintValue)
+		case *Field_DoubleValue:
+			enc.AppendFloat64(value.DoubleValue)
+		case *Field_BoolValue:
+			enc.AppendBool(value.BoolValue)
+		case *Field_StringValue:
+			enc.AppendString(value.StringValue)
+		case *Field_BytesValue:
+			enc.AppendByteString(value.BytesValue)
+		case *Field_JsonValue:
+			if err := enc.AppendReflected(json.RawMessage(value.JsonValue)); err != nil {
+				return err
+			}
+		case *Field_DurationValue:
+			enc.AppendDuration(time.Duration(value.DurationValue))
+		case *Field_TimeValue:
+			enc.AppendTime(time.Unix(0, value.TimeValue))
+		case *Field_ObjectValue:
+			if err := enc.AppendObject(value.ObjectValue); err != nil {
+				return err
+			}
+		case *Field_ArrayValue:
+			if err := enc.AppendArray(value.ArrayValue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRenderLogBody(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cases := []struct {
+		name  string
+		field *Field
+		want  interface{}
+	}{
+		{"int", &Field{Key: "k", Value: &Field_IntValue{IntValue: -3}}, float64(-3)},
+		{"uint", &Field{Key: "k", Value: &Field_UintValue{UintValue: 7}}, float64(7)},
+		{"double", &Field{Key: "k", Value: &Field_DoubleValue{DoubleValue: 1.5}}, 1.5},
+		{"bool", &Field{Key: "k", Value: &Field_BoolValue{BoolValue: true}}, true},
+		{"string", &Field{Key: "k", Value: &Field_StringValue{StringValue: "v"}}, "v"},
+		{"bytes", &Field{Key: "k", Value: &Field_BytesValue{BytesValue: []byte("ab")}}, "YWI="},
+		{"json", &Field{Key: "k", Value: &Field_JsonValue{JsonValue: `{"a":1}`}}, map[string]interface{}{"a": float64(1)}},
+		{"duration", &Field{Key: "k", Value: &Field_DurationValue{DurationValue: int64(time.Second)}}, "1s"},
+		{"time", &Field{Key: "k", Value: &Field_TimeValue{TimeValue: now.UnixNano()}}, now.In(TimeLocation).Format("2006-01-02T15:04:05.000")},
+		{"object", &Field{Key: "k", Value: &Field_ObjectValue{ObjectValue: &FieldObject{Fields: []*Field{
+			{Key: "a", Value: &Field_StringValue{StringValue: "b"}},
+		}}}}, map[string]interface{}{"a": "b"}},
+		{"array", &Field{Key: "k", Value: &Field_ArrayValue{ArrayValue: &FieldArray{Elements: []*Field{
+			{Value: &Field_IntValue{IntValue: 1}},
+			{Value: &Field_StringValue{StringValue: "x"}},
+			{Value: &Field_JsonValue{JsonValue: `[2]`}},
+		}}}}, []interface{}{float64(1), "x", []interface{}{float64(2)}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			body := &LogBody{
+				Level:      1,
+				Time:       now.UnixNano(),
+				LoggerName: "svc",
+				Message:    "hello",
+				Fields:     []*Field{c.field},
+			}
+			buf, err := RenderLogBody(NewEncoder(FormatJSON), body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer buf.Free()
+			m := make(map[string]interface{})
+			if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+				t.Fatalf("%s: %v", buf.String(), err)
+			}
+			if m["msg"] != "hello" || m["level"] != "warn" || m["logger"] != "svc" {
+				t.Fatalf("unexpected entry: %s", buf.String())
+			}
+			if !reflect.DeepEqual(m["k"], c.want) {
+				t.Fatalf("expected %#v, got %#v", c.want, m["k"])
+			}
+		})
+	}
+}
@@ -1,23 +1,365 @@
 package log
 
-import "go.uber.org/zap/zapcore"
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+const (
+	pbBackupTimeFormat = "2006-01-02T15-04-05.000"
+	pbCompressSuffix   = ".gz"
+	megabyte           = 1024 * 1024
+)
+
+// PBWrite protobuf 日志文件写入器,每次 Write 写入一条 protobuf 编码器输出的带长度前缀的 LogBody,
+// 一条记录不会被拆分到两个文件,文件轮转规则与 lumberjack 一致
 type PBWrite interface {
 	zapcore.WriteSyncer
+	io.Closer
+	// Rotate 关闭当前文件并备份,之后的写入使用新文件
+	Rotate() error
+	// SetConfig 更新文件配置,下一次写入时生效
+	SetConfig(conf FileLogConfig)
 }
 
-func newPBWrite() PBWrite {
-	impl := &pbWriteImpl{}
+func newPBWrite(conf FileLogConfig) PBWrite {
+	impl := &pbWriteImpl{conf: conf}
 	return impl
 }
 
 type pbWriteImpl struct {
+	mutex     sync.Mutex
+	millMutex sync.Mutex
+	conf      FileLogConfig
+	file      *os.File
+	size      int64
+	// millWait 等待正在执行的备份清理
+	millWait sync.WaitGroup
+}
+
+func (impl *pbWriteImpl) SetConfig(conf FileLogConfig) {
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+	if conf.FileName != impl.conf.FileName {
+		_ = impl.close()
+	}
+	impl.conf = conf
 }
 
 func (impl *pbWriteImpl) Write(p []byte) (n int, err error) {
-	panic("implement me")
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+	writeLen := int64(len(p))
+	if writeLen > impl.max() {
+		return 0, fmt.Errorf("日志长度 %d 超过了文件最大限制 %d", writeLen, impl.max())
+	}
+	if impl.file == nil {
+		if err = impl.openExistingOrNew(writeLen); err != nil {
+			return 0, err
+		}
+	}
+	if impl.size+writeLen > impl.max() {
+		if err = impl.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err = impl.file.Write(p)
+	impl.size += int64(n)
+	return n, err
 }
 
 func (impl *pbWriteImpl) Sync() error {
-	panic("implement me")
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+	if impl.file == nil {
+		return nil
+	}
+	return impl.file.Sync()
+}
+
+func (impl *pbWriteImpl) Close() error {
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+	return impl.close()
+}
+
+func (impl *pbWriteImpl) Rotate() error {
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+	return impl.rotate()
+}
+
+func (impl *pbWriteImpl) close() error {
+	if impl.file == nil {
+		return nil
+	}
+	err := impl.file.Sync()
+	if e := impl.file.Close(); err == nil {
+		err = e
+	}
+	impl.file = nil
+	impl.size = 0
+	return err
+}
+
+func (impl *pbWriteImpl) rotate() error {
+	if err := impl.close(); err != nil {
+		return err
+	}
+	if err := impl.openNew(); err != nil {
+		return err
+	}
+	impl.millWait.Add(1)
+	go func() {
+		defer impl.millWait.Done()
+		impl.mill()
+	}()
+	return nil
+}
+
+func (impl *pbWriteImpl) openNew() error {
+	name := impl.filename()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return fmt.Errorf("创建日志目录失败: %w", err)
+	}
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(name); err == nil {
+		mode = info.Mode()
+		if err := os.Rename(name, impl.backupName(name)); err != nil {
+			return fmt.Errorf("备份日志文件失败: %w", err)
+		}
+	}
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	impl.file = f
+	impl.size = 0
+	return nil
+}
+
+func (impl *pbWriteImpl) openExistingOrNew(writeLen int64) error {
+	name := impl.filename()
+	info, err := os.Stat(name)
+	if os.IsNotExist(err) {
+		return impl.openNew()
+	}
+	if err != nil {
+		return fmt.Errorf("获取日志文件信息失败: %w", err)
+	}
+	if info.Size()+writeLen >= impl.max() {
+		return impl.rotate()
+	}
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return impl.openNew()
+	}
+	impl.file = f
+	impl.size = info.Size()
+	return nil
+}
+
+func (impl *pbWriteImpl) filename() string {
+	if impl.conf.FileName != "" {
+		return impl.conf.FileName
+	}
+	return filepath.Join(os.TempDir(), filepath.Base(os.Args[0])+"-pblog.log")
+}
+
+func (impl *pbWriteImpl) max() int64 {
+	if impl.conf.Maxsize <= 0 {
+		return int64(100 * megabyte)
+	}
+	return int64(impl.conf.Maxsize) * int64(megabyte)
+}
+
+func (impl *pbWriteImpl) prefixAndExt() (string, string) {
+	filename := filepath.Base(impl.filename())
+	ext := filepath.Ext(filename)
+	return filename[:len(filename)-len(ext)] + "-", ext
+}
+
+// backupName 备份文件名,同一毫秒内多次轮转时在时间后加上序号,避免覆盖之前的备份
+func (impl *pbWriteImpl) backupName(name string) string {
+	dir := filepath.Dir(name)
+	prefix, ext := impl.prefixAndExt()
+	timestamp := time.Now().Format(pbBackupTimeFormat)
+	backup := filepath.Join(dir, prefix+timestamp+ext)
+	for seq := 1; pbBackupExists(backup); seq++ {
+		backup = filepath.Join(dir, prefix+timestamp+"."+strconv.Itoa(seq)+ext)
+	}
+	return backup
+}
+
+func pbBackupExists(name string) bool {
+	for _, file := range []string{name, name + pbCompressSuffix} {
+		if _, err := os.Lstat(file); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseBackupTimestamp 解析备份文件名中去掉前缀和扩展名后的时间部分,
+// 返回备份时间和同一毫秒内的序号
+func ParseBackupTimestamp(ts string) (time.Time, int, error) {
+	if len(ts) < len(pbBackupTimeFormat) {
+		return time.Time{}, 0, fmt.Errorf("不是备份文件的时间: %s", ts)
+	}
+	t, err := time.ParseInLocation(pbBackupTimeFormat, ts[:len(pbBackupTimeFormat)], time.Local)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	seq := 0
+	if rest := ts[len(pbBackupTimeFormat):]; rest != "" {
+		if !strings.HasPrefix(rest, ".") {
+			return time.Time{}, 0, fmt.Errorf("不是备份文件的时间: %s", ts)
+		}
+		if seq, err = strconv.Atoi(rest[1:]); err != nil || seq <= 0 {
+			return time.Time{}, 0, fmt.Errorf("不是备份文件的时间: %s", ts)
+		}
+	}
+	return t, seq, nil
+}
+
+type pbBackupFile struct {
+	name      string
+	timestamp time.Time
+	seq       int
+}
+
+// mill 清理超出数量和时间的备份,并按配置压缩
+func (impl *pbWriteImpl) mill() {
+	impl.millMutex.Lock()
+	defer impl.millMutex.Unlock()
+	impl.mutex.Lock()
+	conf := impl.conf
+	dir := filepath.Dir(impl.filename())
+	prefix, ext := impl.prefixAndExt()
+	impl.mutex.Unlock()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	backups := make([]pbBackupFile, 0)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		ts := strings.TrimPrefix(name, prefix)
+		if ts == name {
+			continue
+		}
+		ts = strings.TrimSuffix(strings.TrimSuffix(ts, pbCompressSuffix), ext)
+		t, seq, err := ParseBackupTimestamp(ts)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, pbBackupFile{name: filepath.Join(dir, name), timestamp: t, seq: seq})
+	}
+	// 最新的备份排在前面
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].timestamp.Equal(backups[j].timestamp) {
+			return backups[i].seq > backups[j].seq
+		}
+		return backups[i].timestamp.After(backups[j].timestamp)
+	})
+	cutoff := time.Now().Add(-time.Duration(conf.MaxAge) * 24 * time.Hour)
+	for i, backup := range backups {
+		if (conf.MaxBackups > 0 && i >= conf.MaxBackups) || (conf.MaxAge > 0 && backup.timestamp.Before(cutoff)) {
+			_ = os.Remove(backup.name)
+			continue
+		}
+		if conf.Compress && !strings.HasSuffix(backup.name, pbCompressSuffix) {
+			_ = compressPBLogFile(backup.name, backup.name+pbCompressSuffix)
+		}
+	}
+}
+
+func compressPBLogFile(src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gzf, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(gzf)
+	if _, err = io.Copy(gz, f); err == nil {
+		err = gz.Close()
+	}
+	if e := gzf.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
+
+// LogBodyReader 顺序读取 PBWrite 写入的日志记录
+type LogBodyReader struct {
+	reader *bufio.Reader
+	body   *LogBody
+	err    error
+}
+
+// ReadLogBodies 创建一个读取长度前缀 LogBody 流的迭代器,
+// 用法与 bufio.Scanner 类似:
+//
+//	reader := log.ReadLogBodies(f)
+//	for reader.Next() {
+//		body := reader.LogBody()
+//	}
+//	err := reader.Err()
+func ReadLogBodies(r io.Reader) *LogBodyReader {
+	reader, ok := r.(*bufio.Reader)
+	if !ok {
+		reader = bufio.NewReader(r)
+	}
+	return &LogBodyReader{reader: reader}
+}
+
+// Next 读取下一条记录,读取结束或出错时返回 false
+func (r *LogBodyReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	body := &LogBody{}
+	if err := (protodelim.UnmarshalOptions{MaxSize: -1}).UnmarshalFrom(r.reader, body); err != nil {
+		r.body = nil
+		r.err = err
+		return false
+	}
+	r.body = body
+	return true
+}
+
+// LogBody 返回最近一次 Next 读取的记录
+func (r *LogBodyReader) LogBody() *LogBody {
+	return r.body
+}
+
+// Err 返回读取过程中遇到的错误,正常读取到结尾时返回 nil
+func (r *LogBodyReader) Err() error {
+	if r.err == io.EOF {
+		return nil
+	}
+	return r.err
 }
@@ -0,0 +1,122 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestPBWrite(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "service.log")
+	write := newPBWrite(FileLogConfig{FileName: fileName, Enable: true, Maxsize: 1})
+	logger := zap.New(zapcore.NewCore(newPBEncoder(newEncodeConfig()), write, zapcore.DebugLevel))
+	for i := 0; i < 3; i++ {
+		logger.Info("hello", zap.Int("index", i))
+	}
+	if err := write.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := write.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	reader := ReadLogBodies(f)
+	count := 0
+	for reader.Next() {
+		body := reader.LogBody()
+		if body.Message != "hello" || body.Fields[0].GetIntValue() != int64(count) {
+			t.Fatalf("unexpected body: %v", body)
+		}
+		count++
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Fatalf("expected 3 records, got %d", count)
+	}
+}
+
+func TestPBWriteRotate(t *testing.T) {
+	dir := t.TempDir()
+	write := newPBWrite(FileLogConfig{FileName: filepath.Join(dir, "service.log"), Enable: true, MaxBackups: 1})
+	// 同一毫秒内多次轮转,备份文件名不能互相覆盖
+	for i := 0; i < 3; i++ {
+		if _, err := write.Write([]byte(strconv.Itoa(i))); err != nil {
+			t.Fatal(err)
+		}
+		if err := write.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write.Close()
+	write.(*pbWriteImpl).millWait.Wait()
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Fatalf("expected current file and one backup, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Name() == "service.log" {
+			continue
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if string(data) != "2" {
+			t.Fatalf("the newest backup should be kept, got %s with %q", entry.Name(), data)
+		}
+	}
+}
+
+func TestParseBackupTimestamp(t *testing.T) {
+	cases := []struct {
+		ts   string
+		seq  int
+		fail bool
+	}{
+		{ts: "2024-01-02T03-04-05.000"},
+		{ts: "2024-01-02T03-04-05.000.2", seq: 2},
+		{ts: "2024-01-02T03-04-05.000.0", fail: true},
+		{ts: "2024-01-02T03-04-05.000-2", fail: true},
+		{ts: "2024-01-02", fail: true},
+	}
+	for _, c := range cases {
+		_, seq, err := ParseBackupTimestamp(c.ts)
+		if (err != nil) != c.fail || seq != c.seq {
+			t.Fatalf("%s: unexpected result %d, %v", c.ts, seq, err)
+		}
+	}
+}
+
+func TestRegisteredEncoder(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "zap.log")
+	conf := zap.NewProductionConfig()
+	conf.Encoding = FormatProtobuf
+	conf.OutputPaths = []string{fileName}
+	logger, err := conf.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("first")
+	logger.Warn("second", zap.String("k", "v"))
+	_ = logger.Sync()
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	reader := ReadLogBodies(f)
+	var messages []string
+	for reader.Next() {
+		messages = append(messages, reader.LogBody().GetMessage())
+	}
+	if err := reader.Err(); err != nil || len(messages) != 2 || messages[1] != "second" {
+		t.Fatalf("files written through zap.Config should be readable, got %v, %v", messages, err)
+	}
+}
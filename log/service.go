@@ -16,6 +16,8 @@ type Config struct {
 	EnableConsole bool          `mapstructure:"enable_console,omitempty" json:"enable_console,omitempty"`
 	EnableColor   bool          `mapstructure:"enable_color,omitempty" json:"enable_color,omitempty"`
 	EnableSampler bool          `mapstructure:"enable_sampler,omitempty" json:"enable_sampler,omitempty"`
+	// Format 文件日志格式,支持 json(默认)、console、protobuf
+	Format string `mapstructure:"format,omitempty" json:"format,omitempty"`
 }
 
 type FileLogConfig struct {
@@ -0,0 +1,186 @@
+// Package retry 根据 errors.Error 的分类执行带指数退避的重试
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/coffeehc/base/errors"
+	"github.com/coffeehc/base/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Policy 判断错误是否需要重试
+type Policy func(err errors.Error) bool
+
+// DefaultPolicy 默认的重试策略:
+// 业务错误不重试;错误本身标记为可重试时重试,通过 errors.WithRetryable(false) 显式标记为不可重试时不重试;
+// 没有显式标记时网络、RPC、Redis 错误重试,数据库错误只在超时时重试
+func DefaultPolicy(err errors.Error) bool {
+	if err == nil || errors.IsMessageError(err) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if err.IsRetryable() {
+		return true
+	}
+	if errors.IsRetryableSet(err) {
+		return false
+	}
+	switch {
+	case errors.IsNetError(err), errors.IsRPCError(err), errors.IsRedisErro(err):
+		return true
+	case errors.IsDBError(err):
+		return err.IsTimeout()
+	default:
+		return false
+	}
+}
+
+type options struct {
+	name           string
+	maxAttempts    int
+	maxElapsedTime time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+	jitter         float64
+	policy         Policy
+}
+
+// Option 重试的可选项
+type Option func(opts *options)
+
+// WithName 设置重试的名称,用于日志
+func WithName(name string) Option {
+	return func(opts *options) {
+		opts.name = name
+	}
+}
+
+// WithMaxAttempts 设置最多执行的次数(包含第一次),小于等于 0 表示不限制,默认 3 次
+func WithMaxAttempts(attempts int) Option {
+	return func(opts *options) {
+		opts.maxAttempts = attempts
+	}
+}
+
+// WithMaxElapsedTime 设置从第一次执行开始的最长重试时间,0 表示不限制
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(opts *options) {
+		opts.maxElapsedTime = d
+	}
+}
+
+// WithBackoff 设置初始退避时间和最大退避时间,默认 100ms 与 10s
+func WithBackoff(initial, max time.Duration) Option {
+	return func(opts *options) {
+		opts.initialBackoff = initial
+		opts.maxBackoff = max
+	}
+}
+
+// WithMultiplier 设置退避时间的增长倍数,默认 2
+func WithMultiplier(multiplier float64) Option {
+	return func(opts *options) {
+		opts.multiplier = multiplier
+	}
+}
+
+// WithJitter 设置退避时间的随机抖动比例,范围 0-1,默认 0.2,
+// 实际退避时间在 [backoff*(1-jitter), backoff] 之间
+func WithJitter(jitter float64) Option {
+	return func(opts *options) {
+		opts.jitter = jitter
+	}
+}
+
+// WithPolicy 设置重试策略,默认 DefaultPolicy
+func WithPolicy(policy Policy) Option {
+	return func(opts *options) {
+		opts.policy = policy
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		name:           "retry",
+		maxAttempts:    3,
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     10 * time.Second,
+		multiplier:     2,
+		jitter:         0.2,
+		policy:         DefaultPolicy,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	if o.multiplier < 1 {
+		o.multiplier = 1
+	}
+	o.jitter = math.Min(math.Max(o.jitter, 0), 1)
+	return o
+}
+
+// backoff 第 attempt 次失败后的退避时间,错误建议的重试间隔更长时使用建议值
+func (o *options) backoff(attempt int, err errors.Error) time.Duration {
+	d := float64(o.initialBackoff) * math.Pow(o.multiplier, float64(attempt-1))
+	if o.maxBackoff > 0 && d > float64(o.maxBackoff) {
+		d = float64(o.maxBackoff)
+	}
+	d -= d * o.jitter * rand.Float64()
+	backoff := time.Duration(d)
+	if hint := err.RetryAfter(); hint > backoff {
+		backoff = hint
+	}
+	return backoff
+}
+
+// Do 执行 fn,失败时根据策略重试,直到成功、不可重试、达到次数或时间限制、ctx 结束.
+// 返回最后一次执行的错误, ctx 结束且没有执行错误时返回 ctx 的错误
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) errors.Error {
+	o := newOptions(opts)
+	start := time.Now()
+	var lastErr errors.Error
+	for attempt := 1; ; attempt++ {
+		if ctx.Err() != nil {
+			break
+		}
+		err := errors.ConverError(fn(ctx))
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !o.policy(err) {
+			return err
+		}
+		if o.maxAttempts > 0 && attempt >= o.maxAttempts {
+			log.SendLog(zapcore.ErrorLevel, "重试次数已用完", err.GetFields(zap.String("retryName", o.name), zap.Int("attempt", attempt))...)
+			return err
+		}
+		backoff := o.backoff(attempt, err)
+		if o.maxElapsedTime > 0 && time.Since(start)+backoff > o.maxElapsedTime {
+			log.SendLog(zapcore.ErrorLevel, "重试时间已用完", err.GetFields(zap.String("retryName", o.name), zap.Int("attempt", attempt))...)
+			return err
+		}
+		log.SendLog(zapcore.WarnLevel, "执行失败,准备重试", err.GetFields(zap.String("retryName", o.name), zap.Int("attempt", attempt), zap.Duration("backoff", backoff))...)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return lastErr
+		case <-timer.C:
+		}
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return errors.ConverError(ctx.Err())
+}
@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/coffeehc/base/errors"
+)
+
+func TestDo(t *testing.T) {
+	attempts := 0
+	err := Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.BuildError(errors.ErrorSystemNet, "连接失败")
+		}
+		return nil
+	}, WithBackoff(time.Millisecond, 5*time.Millisecond))
+	if err != nil || attempts != 3 {
+		t.Fatalf("should succeed on the third attempt, attempts=%d err=%v", attempts, err)
+	}
+
+	attempts = 0
+	err = Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errors.MessageError("参数错误")
+	}, WithBackoff(time.Millisecond, 5*time.Millisecond))
+	if err == nil || attempts != 1 {
+		t.Fatalf("message errors should not be retried, attempts=%d", attempts)
+	}
+
+	attempts = 0
+	err = Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errors.BuildError(errors.ErrorSystemRPC, "调用失败")
+	}, WithMaxAttempts(4), WithBackoff(time.Millisecond, 5*time.Millisecond))
+	if !errors.IsRPCError(err) || attempts != 4 {
+		t.Fatalf("should stop after max attempts, attempts=%d err=%v", attempts, err)
+	}
+}
+
+func TestDoContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	attempts := 0
+	start := time.Now()
+	err := Do(ctx, func(ctx context.Context) error {
+		attempts++
+		return errors.BuildError(errors.ErrorSystemNet, "连接失败")
+	}, WithMaxAttempts(0), WithBackoff(50*time.Millisecond, time.Second))
+	if !errors.IsNetError(err) || attempts != 1 {
+		t.Fatalf("should return the last error when ctx is done, attempts=%d err=%v", attempts, err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatal("ctx cancellation should interrupt the backoff")
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Do(cancelled, func(ctx context.Context) error { return nil }); err == nil {
+		t.Fatal("cancelled ctx should return an error")
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	if DefaultPolicy(errors.BuildError(errors.ErrorSystemDB, "唯一键冲突")) {
+		t.Fatal("db errors are not retryable unless timeout")
+	}
+	if !DefaultPolicy(errors.BuildError(errors.ErrorSystemDB, "查询超时", errors.WithTimeout())) {
+		t.Fatal("db timeout should be retryable")
+	}
+	if !DefaultPolicy(errors.BuildError(errors.ErrorSystem, "稍后重试", errors.WithRetryable(true))) {
+		t.Fatal("explicitly retryable errors should be retried")
+	}
+	if DefaultPolicy(errors.ConverError(fmt.Errorf("decode body: %w", io.EOF))) {
+		t.Fatal("io.EOF should not be retried")
+	}
+	if !DefaultPolicy(errors.BuildError(errors.ErrorSystemNet, "连接重置")) {
+		t.Fatal("net errors should be retried by default")
+	}
+	if DefaultPolicy(errors.BuildError(errors.ErrorSystemNet, "证书无效", errors.WithRetryable(false))) {
+		t.Fatal("explicitly non-retryable net errors should not be retried")
+	}
+	notRetryable := errors.BuildError(errors.ErrorSystemRPC, "参数无法序列化", errors.WithRetryable(false))
+	if DefaultPolicy(errors.WrappedError(errors.ErrorSystemRPC, notRetryable)) {
+		t.Fatal("explicit flag should be inherited from the cause")
+	}
+	if DefaultPolicy(errors.ParseError(notRetryable.FormatRPCError())) {
+		t.Fatal("explicit flag should survive serialization")
+	}
+	o := newOptions([]Option{WithBackoff(time.Millisecond, time.Second), WithJitter(0)})
+	if d := o.backoff(3, errors.BuildError(errors.ErrorSystemNet, "x")); d != 4*time.Millisecond {
+		t.Fatalf("unexpected backoff %s", d)
+	}
+	if d := o.backoff(1, errors.BuildError(errors.ErrorSystemNet, "x", errors.WithRetryAfter(time.Second))); d != time.Second {
+		t.Fatalf("retry-after hint should be honoured, got %s", d)
+	}
+}
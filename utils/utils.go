@@ -17,19 +17,19 @@ func GetLocalIP() (net.IP, errors.Error) {
 		netInterface, err := net.InterfaceByName(interfaceName)
 		if err != nil {
 			log.Error("获取指定网络接口失败", zap.String("interfaceName", interfaceName))
-			return net.IPv4zero, errors.SystemError("获取指定网络接口失败")
+			return net.IPv4zero, errors.BuildError(errors.ErrorSystem, "获取指定网络接口失败", errors.WithMessageKey("base.utils.interface_notfound"))
 		}
 		addrs, err := netInterface.Addrs()
 		if err != nil || len(addrs) == 0 {
 			log.Error("获取指定网络接口地址失败", zap.String("interfaceName", interfaceName))
-			return net.IPv4zero, errors.SystemError("获取指定网络接口地址失败")
+			return net.IPv4zero, errors.BuildError(errors.ErrorSystem, "获取指定网络接口地址失败", errors.WithMessageKey("base.utils.interface_addr"))
 		}
 		return getActiveIP(addrs)
 	}
 	addrs, err := net.InterfaceAddrs()
 	if err != nil || len(addrs) == 0 {
 		log.Error("获取本地Ip失败")
-		return net.IPv4zero, errors.SystemError("获取本地Ip地址失败")
+		return net.IPv4zero, errors.BuildError(errors.ErrorSystem, "获取本地Ip地址失败", errors.WithMessageKey("base.utils.local_ip"))
 	}
 	return getActiveIP(addrs)
 }
@@ -42,16 +42,16 @@ func getActiveIP(addrs []net.Addr) (net.IP, errors.Error) {
 			}
 		}
 	}
-	return net.IPv4zero, errors.SystemError("没有可用的有效 Ip")
+	return net.IPv4zero, errors.BuildError(errors.ErrorSystem, "没有可用的有效 Ip", errors.WithMessageKey("base.utils.no_active_ip"))
 }
 
 func WarpServiceAddr(serviceAddr string) (string, errors.Error) {
 	if serviceAddr == "" {
-		return "", errors.SystemError("服务地址不能为空")
+		return "", errors.BuildError(errors.ErrorSystem, "服务地址不能为空", errors.WithMessageKey("base.utils.empty_addr"))
 	}
 	addr, err := net.ResolveTCPAddr("tcp4", serviceAddr)
 	if err != nil {
-		return "", errors.SystemError(fmt.Sprintf("服务地址不是一个标准的tcp地址:%s", err))
+		return "", errors.BuildError(errors.ErrorSystem, fmt.Sprintf("服务地址不是一个标准的tcp地址:%s", err), errors.WithMessageKey("base.utils.invalid_addr"))
 	}
 	if addr.IP.Equal(net.IPv4zero) {
 		localIp, err := GetLocalIP()